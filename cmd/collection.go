@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/collection"
+	client "github.com/JoaoPedr0Maciel/charm/internal/http"
+	"github.com/JoaoPedr0Maciel/charm/internal/httpfile"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+	"github.com/JoaoPedr0Maciel/charm/internal/vars"
+	"github.com/spf13/cobra"
+)
+
+func createCollectionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collection",
+		Short: "Run multi-step request collections",
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run [collection.yaml]",
+		Short: "Run the steps of a collection in order, chaining captured variables",
+		Long: `Run the steps of a collection in order. Each step may capture values from
+its response into variables used by the following steps:
+
+  capture:
+    token: $.access_token     JSONPath into the JSON body
+    location: header Location response header
+    code: status              status code
+
+The run stops at the first step that fails or cannot capture a value.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runCollection,
+	}
+	addCommonFlags(runCmd)
+
+	cmd.AddCommand(runCmd)
+	return cmd
+}
+
+func runCollection(cmd *cobra.Command, args []string) error {
+	c, err := collection.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	cliVariables, err := loadVariables(cmd)
+	if err != nil {
+		return err
+	}
+
+	variables, err := collectionVariables(c, cliVariables)
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	results := make([]structs.StepResult, len(c.Steps))
+	startTime := time.Now()
+
+	var runErr error
+	for i, step := range c.Steps {
+		results[i] = structs.StepResult{Name: step.Name, Method: step.Method, Skipped: runErr != nil}
+		if runErr != nil {
+			continue
+		}
+
+		ui.DisplayRunStep(i+1, len(c.Steps), step.Name, step.Method+" "+step.URL)
+
+		if err := runStep(cmd, step, variables, &results[i]); err != nil {
+			results[i].Error = err.Error()
+			runErr = fmt.Errorf("collection stopped at %s: %w", step.Name, err)
+		}
+	}
+
+	ui.DisplaySteps(c.Name, results, time.Since(startTime), output)
+	return runErr
+}
+
+// runStep sends one step and stores its captures in variables for the next steps.
+func runStep(cmd *cobra.Command, step collection.Step, variables vars.Variables, result *structs.StepResult) error {
+	request := httpfile.Request{
+		Method:   step.Method,
+		URL:      step.URL,
+		Headers:  http.Header{},
+		Body:     step.Body,
+		BodyFile: step.BodyFile,
+	}
+	for name, value := range step.Headers {
+		request.Headers.Set(name, value)
+	}
+
+	opts, err := buildFileRequestOptions(cmd, request, variables)
+	if err != nil {
+		return err
+	}
+	// Captures read the body, so event streams are read whole.
+	opts.KeepBody = true
+
+	stepStart := time.Now()
+	resp, err := client.MakeRequest(opts)
+	result.Duration = time.Since(stepStart)
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	captured, err := step.CaptureFrom(resp, body)
+	variables.Merge(captured)
+	for name := range captured {
+		result.Captured = append(result.Captured, name)
+	}
+	sort.Strings(result.Captured)
+
+	return err
+}
+
+// collectionVariables resolves the collection vars in file order, so each may
+// use the ones above it, --var, --env-file and the environment; --var and
+// --env-file values win over the file.
+func collectionVariables(c *collection.Collection, cliVariables vars.Variables) (vars.Variables, error) {
+	variables := vars.Variables{}.Merge(cliVariables)
+
+	for _, variable := range c.Vars {
+		if _, ok := cliVariables[variable.Name]; ok {
+			continue
+		}
+
+		expanded, err := variables.Expand(variable.Value)
+		if err != nil {
+			return nil, fmt.Errorf("vars.%s: %w", variable.Name, err)
+		}
+		variables[variable.Name] = expanded
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/graphql"
+	client "github.com/JoaoPedr0Maciel/charm/internal/http"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/spf13/cobra"
+)
+
+func createGraphQLCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graphql [url]",
+		Short: "Send a GraphQL query or mutation",
+		Long: `Send a GraphQL query or mutation, building the JSON envelope
+({"query", "variables", "operationName"}) from the flags.
+
+--query and --variables accept inline text, @file or @- for stdin. The
+response data and errors are shown separately; with --fail, a response
+with errors fails even when the status is 200.
+
+--introspect lists the types and fields of the schema instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGraphQL,
+	}
+
+	cmd.Flags().String("query", "", "GraphQL document: inline, @file.graphql or @- for stdin")
+	cmd.Flags().String("variables", "", "Variables as a JSON object: inline, @file.json or @- for stdin")
+	cmd.Flags().String("operation", "", "Operation to run when the document defines several")
+	cmd.Flags().Bool("introspect", false, "Run an introspection query and list the schema types and fields")
+	addSessionFlags(cmd)
+	addHTTPFlags(cmd)
+
+	return cmd
+}
+
+func runGraphQL(cmd *cobra.Command, args []string) error {
+	opts, err := buildRequestOptionsWithQuery(cmd, args[0], nil)
+	if err != nil {
+		return err
+	}
+
+	rawQuery, _ := cmd.Flags().GetString("query")
+	rawVariables, _ := cmd.Flags().GetString("variables")
+	operation, _ := cmd.Flags().GetString("operation")
+	introspect, _ := cmd.Flags().GetBool("introspect")
+
+	if introspect {
+		if rawQuery != "" || operation != "" {
+			return fmt.Errorf("--introspect cannot be combined with --query or --operation")
+		}
+		rawQuery, operation = graphql.IntrospectionQuery, "IntrospectionQuery"
+	} else if rawQuery == "" {
+		return fmt.Errorf("--query is required (inline, @file.graphql or @- for stdin), or use --introspect")
+	}
+
+	if rawQuery == "@-" && rawVariables == "@-" {
+		return fmt.Errorf("--query and --variables cannot both be read from stdin")
+	}
+
+	query, err := readFlagValue(rawQuery)
+	if err != nil {
+		return err
+	}
+
+	variablesText, err := readFlagValue(rawVariables)
+	if err != nil {
+		return err
+	}
+
+	variables, err := loadVariables(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := expandVariables(&opts, variables); err != nil {
+		return err
+	}
+
+	// {{name}} placeholders in the document and variables are expanded too;
+	// GraphQL's own $variables are left alone. Values placed in the JSON
+	// variables are escaped so they cannot break its strings.
+	if query, err = variables.Expand(query); err != nil {
+		return err
+	}
+	if variablesText, err = variables.ExpandJSON(variablesText); err != nil {
+		return err
+	}
+
+	graphQLVariables, err := graphql.ParseVariables(variablesText)
+	if err != nil {
+		return err
+	}
+
+	opts.Data, err = graphql.Request{
+		Query:         query,
+		Variables:     graphQLVariables,
+		OperationName: operation,
+	}.Envelope()
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+
+	if opts.ContentType == "" {
+		opts.ContentType = "application/json"
+	}
+	opts.GraphQL = &structs.GraphQLOptions{Operation: operation, Introspection: introspect}
+	opts.Expect.NoGraphQLErrors = opts.Expect.Fail
+
+	if _, err := client.Post(opts); err != nil {
+		return fmt.Errorf("graphql request failed: %w", err)
+	}
+
+	return nil
+}
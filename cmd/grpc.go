@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func createGRPCCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grpc [host:port] [package.Service/Method]",
+		Short: "Call a gRPC method with JSON messages",
+		Long: `Call a gRPC method, writing the request and reading the responses as JSON.
+
+The schema comes from server reflection, or from .proto files given with
+--proto (imports are resolved against --import-path and the well-known
+types). --list shows the services and methods instead of calling one.
+
+--data accepts inline JSON, @file or @- for stdin; client streaming methods
+take several JSON objects in a row, one per message. Headers and auth flags
+are sent as metadata. TLS is used unless --plaintext is given.
+
+With --web the call goes over gRPC-Web to an http(s):// URL, e.g. an Envoy
+proxy in front of the service; it needs --proto.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runGRPC,
+	}
+
+	cmd.Flags().StringP("data", "d", "", "Request message(s) as JSON: inline, @file.json or @- for stdin")
+	cmd.Flags().StringArray("proto", nil, "Load the schema from this .proto file instead of server reflection (repeatable)")
+	cmd.Flags().StringArray("import-path", nil, "Directory to resolve .proto imports from (repeatable, defaults to the current directory)")
+	cmd.Flags().Bool("plaintext", false, "Connect without TLS (h2c)")
+	cmd.Flags().Bool("web", false, "Call the method over gRPC-Web; the target is an http(s):// URL")
+	cmd.Flags().Bool("list", false, "List the services and methods instead of calling one")
+	cmd.Flags().Bool("fail", false, "Exit with an error when the call ends with a status other than OK")
+	addSessionFlags(cmd)
+
+	return cmd
+}
+
+func runGRPC(cmd *cobra.Command, args []string) error {
+	grpcOpts, err := buildGRPCOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	opts, err := buildRequestOptions(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	rawData, _ := cmd.Flags().GetString("data")
+	data, err := readFlagValue(rawData)
+	if err != nil {
+		return err
+	}
+
+	variables, err := loadVariables(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := expandVariables(&opts, variables); err != nil {
+		return err
+	}
+
+	// The messages are JSON, so values are escaped to stay inside their strings.
+	if opts.Data, err = variables.ExpandJSON(data); err != nil {
+		return err
+	}
+
+	return utils.GRPC(opts, grpcOpts)
+}
+
+func buildGRPCOptions(cmd *cobra.Command, args []string) (structs.GRPCOptions, error) {
+	protoFiles, _ := cmd.Flags().GetStringArray("proto")
+	importPaths, _ := cmd.Flags().GetStringArray("import-path")
+	plaintext, _ := cmd.Flags().GetBool("plaintext")
+	web, _ := cmd.Flags().GetBool("web")
+	list, _ := cmd.Flags().GetBool("list")
+
+	grpcOpts := structs.GRPCOptions{
+		ProtoFiles:  protoFiles,
+		ImportPaths: importPaths,
+		Plaintext:   plaintext,
+		Web:         web,
+		List:        list,
+	}
+
+	switch {
+	case list && len(args) > 1:
+		return grpcOpts, fmt.Errorf("--list takes only the target, not a method")
+	case !list && len(args) < 2:
+		return grpcOpts, fmt.Errorf("missing method: use package.Service/Method, or --list to see the available ones")
+	case web && plaintext:
+		return grpcOpts, fmt.Errorf("--plaintext does not apply to --web: use an http:// URL instead")
+	}
+
+	// Native calls dial the target directly over HTTP/2; only gRPC-Web goes
+	// through the HTTP client, its proxy and its response header timeout.
+	if !web {
+		for _, name := range []string{"proxy", "proxy-user", "noproxy", "timeout"} {
+			if cmd.Flags().Changed(name) {
+				return grpcOpts, fmt.Errorf("--%s is only supported with --web", name)
+			}
+		}
+	}
+
+	if len(args) > 1 {
+		grpcOpts.Method = args[1]
+	}
+	return grpcOpts, nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// applyProfile loads the selected profile and uses its settings as defaults
+// for every flag that was not given explicitly on the command line.
+func applyProfile(cmd *cobra.Command) (*config.Profile, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	profileName, _ := cmd.Flags().GetString("profile")
+
+	if configPath == "" {
+		configPath = config.DefaultPath()
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := cfg.Profile(profileName)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+
+	values := profile.FlagValues()
+	// --bearer or --basic on the command line replaces the profile's auth of
+	// either kind, since bearer would otherwise win over an explicit --basic.
+	if cmd.Flags().Changed("bearer") || cmd.Flags().Changed("basic") {
+		delete(values, "bearer")
+		delete(values, "basic")
+	}
+
+	for name, value := range values {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return nil, fmt.Errorf("invalid %s in profile: %w", name, err)
+		}
+	}
+
+	return profile, nil
+}
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
 
 	client "github.com/JoaoPedr0Maciel/charm/internal/http"
 	"github.com/JoaoPedr0Maciel/charm/internal/structs"
 	"github.com/JoaoPedr0Maciel/charm/internal/updater"
+	"github.com/JoaoPedr0Maciel/charm/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -21,55 +27,125 @@ func SetVersion(v string) {
 var rootCmd = &cobra.Command{
 	Use:   "charm",
 	Short: "Charm is a tool for making HTTP requests",
-	Long:  `Charm is a tool to get beautiful and colorful HTTP requests`,
+	Long: `Charm is a tool to get beautiful and colorful HTTP requests
+
+Exit codes: 0 success, 1 invalid usage or setup, 2 transport error
+(DNS, connect, TLS, timeout), 3 failed --fail/--expect-* assertion.`,
+	SilenceErrors: true,
+	// Arguments are validated before this runs, so usage is still shown for
+	// bad invocations but not when the request itself fails.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+	},
 }
 
 var httpMethods = []structs.HTTPMethod{
-	{Name: "get", HasBody: false, HTTPFuncNoBody: client.Get},
+	{Name: "get", HasBody: false, HTTPFunc: client.Get},
 	{Name: "post", HasBody: true, HTTPFunc: client.Post},
 	{Name: "put", HasBody: true, HTTPFunc: client.Put},
 	{Name: "patch", HasBody: true, HTTPFunc: client.Patch},
 	{Name: "delete", HasBody: true, HTTPFunc: client.Delete},
+	{Name: "head", HasBody: false, HTTPFunc: client.Head},
+	{Name: "options", HasBody: false, HTTPFunc: client.Options},
 }
 
+const requestItemsHelp = `Extra arguments are request items:
+  name=value      JSON string field (nested: user[address][city]=X, arrays: tags[]=a)
+  name:=json      raw JSON field (age:=30, active:=true, tags:='["a"]')
+  name==value     query parameter
+  Header:value    request header`
+
 func createHTTPCommand(method structs.HTTPMethod) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   fmt.Sprintf("%s [url]", method.Name),
+		Use:   fmt.Sprintf("%s [url] [items...]", method.Name),
 		Short: fmt.Sprintf("Make a %s request to the specified URL", method.Name),
-		Args:  cobra.ExactArgs(1),
+		Long:  fmt.Sprintf("Make a %s request to the specified URL.\n\n%s", strings.ToUpper(method.Name), requestItemsHelp),
+		Args:  cobra.MinimumNArgs(1),
 		RunE:  makeHTTPRequestFunc(method),
 	}
 
 	addCommonFlags(cmd)
 
 	if method.HasBody {
-		cmd.Flags().String("data", "", "Request body data (JSON)")
-		cmd.Flags().StringP("data-raw", "d", "", "Request body data (raw)")
+		addBodyFlags(cmd)
 	}
 
 	return cmd
 }
 
+func createCustomMethodCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "request [url] [items...]",
+		Short: "Make a request with any HTTP method (e.g. PROPFIND, PURGE)",
+		Long:  "Make a request with any HTTP method given by -X/--method.\n\n" + requestItemsHelp,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("method")
+			name = strings.ToUpper(strings.TrimSpace(name))
+			if name == "" {
+				return fmt.Errorf("--method cannot be empty")
+			}
+
+			method := structs.HTTPMethod{
+				Name:    name,
+				HasBody: true,
+				HTTPFunc: func(opts structs.RequestOptions) (*http.Response, error) {
+					opts.Method = name
+					return client.MakeRequest(opts)
+				},
+			}
+
+			return makeHTTPRequestFunc(method)(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringP("method", "X", "GET", "HTTP method to use")
+	addCommonFlags(cmd)
+	addBodyFlags(cmd)
+
+	return cmd
+}
+
 func makeHTTPRequestFunc(method structs.HTTPMethod) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		url := args[0]
-
-		bearer, _ := cmd.Flags().GetString("bearer")
-		basic, _ := cmd.Flags().GetString("basic")
-		contentType, _ := cmd.Flags().GetString("content-type")
+		opts, err := buildRequestOptions(cmd, args[0])
+		if err != nil {
+			return err
+		}
 
-		var err error
 		if method.HasBody {
-			data, _ := cmd.Flags().GetString("data-raw")
-			if data == "" {
-				data, _ = cmd.Flags().GetString("data")
+			opts.Data, _ = cmd.Flags().GetString("data-raw")
+			if opts.Data == "" {
+				data, _ := cmd.Flags().GetString("data")
+				opts.Data, opts.DataFile = utils.ParseDataArg(data)
+			}
+
+			if err := applyFormFlags(cmd, &opts); err != nil {
+				return err
 			}
-			_, err = method.HTTPFunc(url, bearer, basic, contentType, data)
-		} else {
-			_, err = method.HTTPFuncNoBody(url, bearer, basic, contentType)
 		}
 
+		variables, err := loadVariables(cmd)
 		if err != nil {
+			return err
+		}
+
+		if err := expandVariables(&opts, variables); err != nil {
+			return err
+		}
+
+		// Items are expanded before they are parsed, so values land in the
+		// JSON body encoded like any other field.
+		items, err := expandItems(args[1:], variables)
+		if err != nil {
+			return err
+		}
+
+		if err := applyRequestItems(&opts, items, method); err != nil {
+			return err
+		}
+
+		if _, err := method.HTTPFunc(opts); err != nil {
 			return fmt.Errorf("%s request failed: %w", method.Name, err)
 		}
 
@@ -77,10 +153,395 @@ func makeHTTPRequestFunc(method structs.HTTPMethod) func(*cobra.Command, []strin
 	}
 }
 
+func buildRequestOptions(cmd *cobra.Command, url string) (structs.RequestOptions, error) {
+	rawQuery, _ := cmd.Flags().GetStringArray("query")
+	return buildRequestOptionsWithQuery(cmd, url, rawQuery)
+}
+
+// buildRequestOptionsWithQuery takes the query parameters explicitly, for
+// `charm graphql`, whose --query flag is the GraphQL document.
+func buildRequestOptionsWithQuery(cmd *cobra.Command, url string, rawQuery []string) (structs.RequestOptions, error) {
+	profile, err := applyProfile(cmd)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	bearer, _ := cmd.Flags().GetString("bearer")
+	basic, _ := cmd.Flags().GetString("basic")
+	contentType, _ := cmd.Flags().GetString("content-type")
+	rawHeaders, _ := cmd.Flags().GetStringArray("header")
+	output, _ := cmd.Flags().GetString("output")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	connectTimeout, _ := cmd.Flags().GetDuration("connect-timeout")
+	maxTime, _ := cmd.Flags().GetDuration("max-time")
+	retry, _ := cmd.Flags().GetInt("retry")
+	rawRetryOn, _ := cmd.Flags().GetString("retry-on")
+	retryDelay, _ := cmd.Flags().GetDuration("retry-delay")
+	noFollow, _ := cmd.Flags().GetBool("no-follow")
+	maxRedirects, _ := cmd.Flags().GetInt("max-redirects")
+	keepMethod, _ := cmd.Flags().GetBool("keep-method")
+	keepAuth, _ := cmd.Flags().GetBool("keep-auth")
+	rawProxy, _ := cmd.Flags().GetString("proxy")
+	proxyUser, _ := cmd.Flags().GetString("proxy-user")
+	noProxy, _ := cmd.Flags().GetString("noproxy")
+
+	if !slices.Contains(structs.OutputModes, output) {
+		return structs.RequestOptions{}, fmt.Errorf("invalid --output %q: must be one of %s", output, strings.Join(structs.OutputModes, ", "))
+	}
+
+	headers, err := utils.ParseHeaders(rawHeaders)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	var baseURL string
+	if profile != nil {
+		baseURL = profile.BaseURL
+		for name, value := range profile.Headers {
+			if headers.Get(name) == "" {
+				headers.Set(name, value)
+			}
+		}
+	}
+
+	query, err := utils.ParseQueryParams(rawQuery)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	if retry < 0 {
+		return structs.RequestOptions{}, fmt.Errorf("--retry cannot be negative")
+	}
+
+	retryOn, err := utils.ParseRetryOn(rawRetryOn)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	if maxRedirects < 0 {
+		return structs.RequestOptions{}, fmt.Errorf("--max-redirects cannot be negative")
+	}
+
+	proxy, err := utils.ParseProxyURL(rawProxy, proxyUser)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	tlsOptions, err := buildTLSOptions(cmd)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	tlsInfo, _ := cmd.Flags().GetBool("tls-info")
+	filter, _ := cmd.Flags().GetString("filter")
+	if _, err := utils.CompileFilter(filter); err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	remoteName, _ := cmd.Flags().GetBool("remote-name")
+	resume, _ := cmd.Flags().GetBool("continue")
+
+	if outputFile != "" && remoteName {
+		return structs.RequestOptions{}, fmt.Errorf("-o/--output-file and -O/--remote-name cannot be combined")
+	}
+	if resume && outputFile == "" && !remoteName {
+		return structs.RequestOptions{}, fmt.Errorf("--continue requires -o/--output-file or -O/--remote-name")
+	}
+
+	stream, _ := cmd.Flags().GetBool("stream")
+	reconnect, _ := cmd.Flags().GetBool("reconnect")
+	lastEventID, _ := cmd.Flags().GetString("last-event-id")
+
+	if lastEventID != "" {
+		headers.Set(utils.LastEventIDHeader, lastEventID)
+	}
+
+	expect, err := buildExpectations(cmd)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	if stream && (filter != "" || len(expect.JSON) > 0 || outputFile != "" || remoteName) {
+		return structs.RequestOptions{}, fmt.Errorf("--stream cannot be combined with --filter, --expect-json or -o/-O downloads")
+	}
+
+	// Downloads are streamed to disk, so there is no body left to inspect.
+	if outputFile != "" || remoteName {
+		switch {
+		case filter != "":
+			return structs.RequestOptions{}, fmt.Errorf("--filter cannot be combined with -o/--output-file or -O/--remote-name")
+		case len(expect.JSON) > 0:
+			return structs.RequestOptions{}, fmt.Errorf("--expect-json cannot be combined with -o/--output-file or -O/--remote-name")
+		}
+	}
+
+	return structs.RequestOptions{
+		URL:         url,
+		BaseURL:     baseURL,
+		Bearer:      bearer,
+		Basic:       basic,
+		ContentType: contentType,
+		Headers:     headers,
+		Query:       query,
+		Output:      output,
+
+		Timeout:        timeout,
+		ConnectTimeout: connectTimeout,
+		MaxTime:        maxTime,
+
+		Retry:      retry,
+		RetryOn:    retryOn,
+		RetryDelay: retryDelay,
+
+		NoFollow:     noFollow,
+		MaxRedirects: maxRedirects,
+		KeepMethod:   keepMethod,
+		KeepAuth:     keepAuth,
+
+		Proxy:   proxy,
+		NoProxy: utils.ParseNoProxy(noProxy),
+
+		TLS:     tlsOptions,
+		TLSInfo: tlsInfo,
+
+		Expect: expect,
+		Filter: filter,
+
+		OutputFile: outputFile,
+		RemoteName: remoteName,
+		Resume:     resume,
+
+		Stream:    stream,
+		Reconnect: reconnect,
+	}, nil
+}
+
+func buildTLSOptions(cmd *cobra.Command) (structs.TLSOptions, error) {
+	caCert, _ := cmd.Flags().GetString("cacert")
+	cert, _ := cmd.Flags().GetString("cert")
+	key, _ := cmd.Flags().GetString("key")
+	certPassword, _ := cmd.Flags().GetString("cert-password")
+	insecure, _ := cmd.Flags().GetBool("insecure")
+	tlsMin, _ := cmd.Flags().GetString("tls-min")
+	tlsMax, _ := cmd.Flags().GetString("tls-max")
+	serverName, _ := cmd.Flags().GetString("servername")
+	rawPins, _ := cmd.Flags().GetStringArray("pin-sha256")
+
+	minVersion, err := utils.ParseTLSVersion("--tls-min", tlsMin)
+	if err != nil {
+		return structs.TLSOptions{}, err
+	}
+
+	maxVersion, err := utils.ParseTLSVersion("--tls-max", tlsMax)
+	if err != nil {
+		return structs.TLSOptions{}, err
+	}
+
+	pins, err := utils.ParsePins(rawPins)
+	if err != nil {
+		return structs.TLSOptions{}, err
+	}
+
+	return structs.TLSOptions{
+		CACert:       caCert,
+		Cert:         cert,
+		Key:          key,
+		CertPassword: certPassword,
+		Insecure:     insecure,
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+		ServerName:   serverName,
+		Pins:         pins,
+	}, nil
+}
+
+func buildExpectations(cmd *cobra.Command) (structs.Expectations, error) {
+	fail, _ := cmd.Flags().GetBool("fail")
+	rawStatus, _ := cmd.Flags().GetString("expect-status")
+	rawHeaders, _ := cmd.Flags().GetStringArray("expect-header")
+	rawJSON, _ := cmd.Flags().GetStringArray("expect-json")
+	rawTime, _ := cmd.Flags().GetString("expect-time")
+
+	status, err := utils.ParseExpectStatus(rawStatus)
+	if err != nil {
+		return structs.Expectations{}, err
+	}
+
+	headers, err := utils.ParseExpectHeaders(rawHeaders)
+	if err != nil {
+		return structs.Expectations{}, err
+	}
+
+	jsonChecks, err := utils.ParseExpectJSON(rawJSON)
+	if err != nil {
+		return structs.Expectations{}, err
+	}
+
+	maxTime, err := utils.ParseExpectTime(rawTime)
+	if err != nil {
+		return structs.Expectations{}, err
+	}
+
+	return structs.Expectations{
+		Fail:    fail,
+		Status:  status,
+		Headers: headers,
+		JSON:    jsonChecks,
+		MaxTime: maxTime,
+	}, nil
+}
+
+func applyFormFlags(cmd *cobra.Command, opts *structs.RequestOptions) error {
+	form, _ := cmd.Flags().GetStringArray("form")
+	files, _ := cmd.Flags().GetStringArray("file")
+	if len(form) == 0 && len(files) == 0 {
+		return nil
+	}
+
+	if opts.Data != "" || opts.DataFile != "" {
+		return fmt.Errorf("--form and --file cannot be combined with --data or --data-raw")
+	}
+
+	fields, err := utils.ParseFormFields(form, files)
+	if err != nil {
+		return err
+	}
+
+	opts.Form = fields
+	return nil
+}
+
+func applyRequestItems(opts *structs.RequestOptions, args []string, method structs.HTTPMethod) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	items, err := utils.ParseRequestItems(args)
+	if err != nil {
+		return err
+	}
+
+	for name, values := range items.Headers {
+		for _, value := range values {
+			opts.Headers.Add(name, value)
+		}
+	}
+
+	for key, values := range items.Query {
+		for _, value := range values {
+			opts.Query.Add(key, value)
+		}
+	}
+
+	if !items.HasBody() {
+		return nil
+	}
+
+	if !method.HasBody {
+		return fmt.Errorf("%s requests do not accept body fields", method.Name)
+	}
+
+	if opts.Data != "" || opts.DataFile != "" || len(opts.Form) > 0 {
+		return fmt.Errorf("body fields cannot be combined with --data, --data-raw, --form or --file")
+	}
+
+	opts.Data, err = items.JSONBody()
+	return err
+}
+
+// readFlagValue resolves @file and @- (stdin) values of flags such as
+// graphql --query and grpc --data.
+func readFlagValue(value string) (string, error) {
+	switch {
+	case value == "@-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return string(data), nil
+	case strings.HasPrefix(value, "@"):
+		data, err := os.ReadFile(value[1:])
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", value[1:], err)
+		}
+		return string(data), nil
+	}
+	return value, nil
+}
+
+func addBodyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("data", "", "Request body data (JSON), or @file / @- to stream it as-is from a file or stdin, without {{var}} expansion")
+	cmd.Flags().StringP("data-raw", "d", "", "Request body data (raw)")
+	cmd.Flags().StringArrayP("form", "F", nil, "Form field in format 'key=value' (repeatable)")
+	cmd.Flags().StringArray("file", nil, "File upload in format 'field=@path[;type=mime][;filename=name]' (repeatable, sends multipart/form-data)")
+}
+
+// addCommonFlags registers the flags of the HTTP commands.
 func addCommonFlags(cmd *cobra.Command) {
+	addSessionFlags(cmd)
+	addQueryFlag(cmd)
+	addHTTPFlags(cmd)
+}
+
+// addHTTPFlags registers the request and response flags of the commands that
+// send an HTTP request, without --query, which `charm graphql` uses for the
+// GraphQL document.
+func addHTTPFlags(cmd *cobra.Command) {
+	cmd.Flags().String("content-type", "", "Content-Type header")
+	cmd.Flags().Int("retry", 0, "Number of times to retry a failed request")
+	cmd.Flags().String("retry-on", utils.DefaultRetryOn, "Comma-separated conditions that trigger a retry: status codes (503), classes (5xx) or 'network'")
+	cmd.Flags().Duration("retry-delay", utils.DefaultRetryDelay, "Base delay for exponential backoff between retries (Retry-After takes precedence)")
+	cmd.Flags().Bool("no-follow", false, "Do not follow redirects")
+	cmd.Flags().Int("max-redirects", utils.DefaultMaxRedirects, "Maximum number of redirects to follow")
+	cmd.Flags().Bool("keep-method", false, "Keep the method and body on 301/302 redirects instead of switching to GET")
+	cmd.Flags().Bool("keep-auth", false, "Keep the Authorization and Cookie headers when redirected to another host")
+	cmd.Flags().Bool("fail", false, "Exit with an error when the response status is 4xx or 5xx")
+	cmd.Flags().String("expect-status", "", "Expected status codes or classes, comma-separated (e.g. 201 or 2xx)")
+	cmd.Flags().StringArray("expect-header", nil, "Expected response header in format 'Name: value' (value is a substring) or 'Name' (repeatable)")
+	cmd.Flags().StringArray("expect-json", nil, "jq expression over the JSON body that must yield true, e.g. '.data.id != null' or '.items | length > 0' (repeatable)")
+	cmd.Flags().String("expect-time", "", "Maximum total response time, e.g. <500ms")
+	cmd.Flags().String("filter", "", "jq expression applied to the JSON response body before display, e.g. '.items[] | {id, name}'")
+	cmd.Flags().StringP("output-file", "o", "", "Stream the response body to this file, with a progress bar")
+	cmd.Flags().BoolP("remote-name", "O", false, "Stream the response body to a file named after the Content-Disposition header or the URL")
+	cmd.Flags().BoolP("continue", "C", false, "Resume a partial -o/-O download with a Range request")
+	cmd.Flags().Bool("stream", false, "Print the response body line by line as it arrives (SSE and NDJSON are detected automatically)")
+	cmd.Flags().Bool("reconnect", false, "Reopen a dropped Server-Sent Events stream, resuming with Last-Event-ID")
+	cmd.Flags().String("last-event-id", "", "Last-Event-ID sent when opening a Server-Sent Events stream")
+}
+
+// addSessionFlags registers the flags every command honors, HTTP or not:
+// profiles and variables, auth, headers, time limits, proxy, TLS and output.
+// Flags a command does not register read as their zero value.
+func addSessionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("profile", "P", "", "Config profile to use (defaults to default_profile from the config file)")
+	cmd.Flags().String("config", "", "Config file path (defaults to $CHARM_CONFIG or ~/.config/charm/config.yaml)")
+	cmd.Flags().StringArray("env-file", nil, "Dotenv file with KEY=VALUE variables for {{name}} placeholders (repeatable)")
+	cmd.Flags().StringArray("var", nil, "Variable for {{name}} placeholders in format 'key=value' (repeatable, overrides --env-file)")
 	cmd.Flags().StringP("bearer", "b", "", "Bearer token for authentication")
 	cmd.Flags().String("basic", "", "Basic auth in format 'username:password'")
-	cmd.Flags().StringP("content-type", "H", "", "Content-Type header")
+	cmd.Flags().StringArrayP("header", "H", nil, "Request header in format 'Name: Value' (repeatable)")
+	cmd.Flags().Duration("timeout", 0, "Maximum time to wait for the response headers once the request is sent (e.g. 30s)")
+	cmd.Flags().Duration("connect-timeout", 0, "Maximum time for DNS, TCP connect and TLS handshake (e.g. 5s)")
+	cmd.Flags().Duration("max-time", 0, "Maximum time for each attempt, including the body transfer (e.g. 1m)")
+	cmd.Flags().String("proxy", "", "Proxy URL (http://, https://, socks5:// or socks5h://); defaults to HTTP_PROXY/HTTPS_PROXY")
+	cmd.Flags().String("proxy-user", "", "Proxy credentials in format 'username:password'")
+	cmd.Flags().String("noproxy", "", "Comma-separated hosts, domains or CIDRs that bypass the proxy ('*' disables it)")
+	cmd.Flags().String("cacert", "", "PEM file with CA certificates to trust in addition to the system ones")
+	cmd.Flags().String("cert", "", "Client certificate for mTLS (PEM, or PKCS#12 with .p12/.pfx extension)")
+	cmd.Flags().String("key", "", "Private key (PEM) for --cert")
+	cmd.Flags().String("cert-password", "", "Password for a PKCS#12 --cert")
+	cmd.Flags().BoolP("insecure", "k", false, "Skip TLS certificate verification")
+	cmd.Flags().String("tls-min", "", "Minimum TLS version (1.0, 1.1, 1.2, 1.3)")
+	cmd.Flags().String("tls-max", "", "Maximum TLS version (1.0, 1.1, 1.2, 1.3)")
+	cmd.Flags().String("servername", "", "Server name for SNI and certificate verification")
+	cmd.Flags().Bool("tls-info", false, "Show the negotiated TLS parameters and the server certificate chain")
+	cmd.Flags().StringArray("pin-sha256", nil, "Expected base64 SHA-256 of the server (leaf) public key, 'sha256//' prefix optional (repeatable)")
+	cmd.Flags().String("output", structs.OutputPretty, "Output format: pretty, json (structured document) or raw (response body only)")
+}
+
+func addQueryFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("query", "q", nil, "Query parameter in format 'key=value' (repeatable)")
 }
 
 var versionCmd = &cobra.Command{
@@ -105,13 +566,25 @@ func init() {
 		rootCmd.AddCommand(createHTTPCommand(method))
 	}
 
+	rootCmd.AddCommand(createCustomMethodCommand())
+	rootCmd.AddCommand(createRunCommand())
+	rootCmd.AddCommand(createCollectionCommand())
+	rootCmd.AddCommand(createWebSocketCommand())
+	rootCmd.AddCommand(createGraphQLCommand())
+	rootCmd.AddCommand(createGRPCCommand())
+
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(updateCmd)
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	client "github.com/JoaoPedr0Maciel/charm/internal/http"
+	"github.com/JoaoPedr0Maciel/charm/internal/httpfile"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+	"github.com/JoaoPedr0Maciel/charm/internal/vars"
+	"github.com/spf13/cobra"
+)
+
+func createRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [file.http]",
+		Short: "Run the requests of a .http / .rest file",
+		Long: `Run the requests of a VS Code REST Client .http / .rest file, in order.
+
+Requests are separated by ###, "@name = value" lines declare variables and
+"# @name createUser" labels the request below it for --name. Flags such as
+--bearer, --header or --timeout apply to every request; headers written in
+the file take precedence.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runHTTPFile,
+	}
+
+	cmd.Flags().String("name", "", "Run only the request labelled '# @name <name>'")
+	addCommonFlags(cmd)
+
+	return cmd
+}
+
+func runHTTPFile(cmd *cobra.Command, args []string) error {
+	file, err := httpfile.ParseFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	name, _ := cmd.Flags().GetString("name")
+	requests, err := selectRequests(file, name)
+	if err != nil {
+		return err
+	}
+
+	cliVariables, err := loadVariables(cmd)
+	if err != nil {
+		return err
+	}
+
+	variables, err := fileVariables(file, cliVariables)
+	if err != nil {
+		return err
+	}
+
+	for i, request := range requests {
+		ui.DisplayRunStep(i+1, len(requests), request.Label(), fmt.Sprintf("%s:%d", file.Path, request.Line))
+
+		opts, err := buildFileRequestOptions(cmd, request, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %w", request.Label(), err)
+		}
+
+		if _, err := client.MakeRequest(opts); err != nil {
+			return fmt.Errorf("%s request failed: %w", request.Label(), err)
+		}
+	}
+
+	return nil
+}
+
+func selectRequests(file *httpfile.File, name string) ([]httpfile.Request, error) {
+	if name == "" {
+		return file.Requests, nil
+	}
+
+	var names []string
+	for _, request := range file.Requests {
+		if request.Name == name {
+			return []httpfile.Request{request}, nil
+		}
+		if request.Name != "" {
+			names = append(names, request.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("request %q not found: %s has no '# @name' labels", name, file.Path)
+	}
+	return nil, fmt.Errorf("request %q not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// fileVariables resolves the "@name = value" declarations in order, so each
+// may use the ones above it. --var and --env-file values win over the file.
+func fileVariables(file *httpfile.File, cliVariables vars.Variables) (vars.Variables, error) {
+	variables := vars.Variables{}.Merge(cliVariables)
+
+	for _, variable := range file.Variables {
+		if _, ok := cliVariables[variable.Name]; ok {
+			continue
+		}
+
+		value, err := variables.Expand(variable.Value)
+		if err != nil {
+			return nil, fmt.Errorf("@%s: %w", variable.Name, err)
+		}
+		variables[variable.Name] = value
+	}
+
+	return variables, nil
+}
+
+func buildFileRequestOptions(cmd *cobra.Command, request httpfile.Request, variables vars.Variables) (structs.RequestOptions, error) {
+	opts, err := buildRequestOptions(cmd, request.URL)
+	if err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	opts.Method = request.Method
+	for name, values := range request.Headers {
+		opts.Headers.Del(name)
+		for _, value := range values {
+			opts.Headers.Add(name, value)
+		}
+	}
+
+	opts.Data = request.Body
+	if request.BodyFile != "" {
+		if !request.ExpandBodyFile {
+			opts.DataFile = request.BodyFile
+		} else {
+			data, err := os.ReadFile(request.BodyFile)
+			if err != nil {
+				return structs.RequestOptions{}, fmt.Errorf("failed to read body file: %w", err)
+			}
+			opts.Data = string(data)
+		}
+	}
+
+	if err := expandVariables(&opts, variables); err != nil {
+		return structs.RequestOptions{}, err
+	}
+
+	return opts, nil
+}
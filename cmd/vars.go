@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/utils"
+	"github.com/JoaoPedr0Maciel/charm/internal/vars"
+	"github.com/spf13/cobra"
+)
+
+// loadVariables merges the --env-file and --var values; --var wins.
+func loadVariables(cmd *cobra.Command) (vars.Variables, error) {
+	envFiles, _ := cmd.Flags().GetStringArray("env-file")
+	assignments, _ := cmd.Flags().GetStringArray("var")
+
+	variables := vars.Variables{}
+	for _, path := range envFiles {
+		values, err := vars.LoadEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		variables.Merge(values)
+	}
+
+	values, err := vars.ParseAssignments(assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	return variables.Merge(values), nil
+}
+
+// expandVariables replaces {{name}} placeholders in the URL, auth, headers,
+// query and body of opts, reporting every undefined variable at once.
+func expandVariables(opts *structs.RequestOptions, variables vars.Variables) error {
+	e := &expander{variables: variables}
+
+	opts.URL = e.expand(opts.URL)
+	opts.Bearer = e.expand(opts.Bearer)
+	opts.Basic = e.expand(opts.Basic)
+
+	headers := http.Header{}
+	for name, values := range opts.Headers {
+		for _, value := range values {
+			headers.Add(e.expand(name), e.expand(value))
+		}
+	}
+	opts.Headers = headers
+
+	// A JSON body gets its values escaped, like graphql --variables and grpc
+	// --data, so they cannot break its strings.
+	if bodyIsJSON(opts) {
+		opts.Data = e.expandWith(opts.Data, variables.ExpandJSON)
+	} else {
+		opts.Data = e.expand(opts.Data)
+	}
+
+	query := url.Values{}
+	for key, values := range opts.Query {
+		for _, value := range values {
+			query.Add(e.expand(key), e.expand(value))
+		}
+	}
+	opts.Query = query
+
+	for i, field := range opts.Form {
+		if !field.IsFile() {
+			opts.Form[i].Value = e.expand(field.Value)
+		}
+	}
+
+	return e.err()
+}
+
+// bodyIsJSON tells whether the body is sent as JSON: its --content-type or
+// Content-Type header when given, the application/json default otherwise.
+func bodyIsJSON(opts *structs.RequestOptions) bool {
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = opts.Headers.Get("Content-Type")
+	}
+	if contentType == "" {
+		contentType = utils.DefaultContentType
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// expandItems replaces {{name}} placeholders in request items.
+func expandItems(items []string, variables vars.Variables) ([]string, error) {
+	e := &expander{variables: variables}
+
+	expanded := make([]string, len(items))
+	for i, item := range items {
+		expanded[i] = e.expand(item)
+	}
+	return expanded, e.err()
+}
+
+type expander struct {
+	variables vars.Variables
+	undefined []string
+	failure   error
+}
+
+func (e *expander) expand(text string) string {
+	return e.expandWith(text, e.variables.Expand)
+}
+
+func (e *expander) expandWith(text string, expand func(string) (string, error)) string {
+	expanded, err := expand(text)
+	if err == nil {
+		return expanded
+	}
+
+	var undefinedErr *vars.UndefinedError
+	if errors.As(err, &undefinedErr) {
+		for _, name := range undefinedErr.Names {
+			if !slices.Contains(e.undefined, name) {
+				e.undefined = append(e.undefined, name)
+			}
+		}
+	} else if e.failure == nil {
+		e.failure = err
+	}
+	return text
+}
+
+func (e *expander) err() error {
+	if e.failure != nil {
+		return e.failure
+	}
+	if len(e.undefined) > 0 {
+		return &vars.UndefinedError{Names: e.undefined}
+	}
+	return nil
+}
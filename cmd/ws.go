@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func createWebSocketCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ws [url]",
+		Short: "Open a WebSocket connection and exchange messages",
+		Long: `Open a WebSocket connection (ws://, wss://, or http(s):// which is upgraded)
+using the same auth, header, proxy and TLS flags as HTTP requests.
+
+Messages come from --send, from a --script file or, when neither is given,
+from stdin: type them interactively or pipe them in, one per line. Besides
+plain text, a line may be a directive:
+
+  @binary <hex>         send a binary frame
+  @ping [payload]       send a ping and time the pong
+  @sleep <duration>     pause before the next message
+  @close [code] [text]  close the connection
+
+Blank lines and # comments are skipped; start a line with \ to send it as is.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runWebSocket,
+	}
+
+	cmd.Flags().StringArray("send", nil, "Text message to send after connecting (repeatable, sent in order)")
+	cmd.Flags().String("script", "", "File with messages and directives to send, one per line")
+	cmd.Flags().StringArray("subprotocol", nil, "Subprotocol to offer in Sec-WebSocket-Protocol (repeatable, in order of preference)")
+	cmd.Flags().Duration("ping-interval", 0, "Send a ping at this interval and show the pong round-trip time (e.g. 10s)")
+	cmd.Flags().Duration("wait", utils.DefaultWebSocketWait, "How long to keep listening after the last message is sent (0 listens until the server closes)")
+	addSessionFlags(cmd)
+	addQueryFlag(cmd)
+
+	return cmd
+}
+
+func runWebSocket(cmd *cobra.Command, args []string) error {
+	opts, err := buildRequestOptions(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	variables, err := loadVariables(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := expandVariables(&opts, variables); err != nil {
+		return err
+	}
+
+	wsOpts, err := buildWebSocketOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	for i, message := range wsOpts.Messages {
+		if message.Type != structs.FrameText {
+			continue
+		}
+		text, err := variables.Expand(string(message.Data))
+		if err != nil {
+			return err
+		}
+		wsOpts.Messages[i].Data = []byte(text)
+	}
+
+	return utils.WebSocket(opts, wsOpts)
+}
+
+func buildWebSocketOptions(cmd *cobra.Command) (structs.WebSocketOptions, error) {
+	send, _ := cmd.Flags().GetStringArray("send")
+	script, _ := cmd.Flags().GetString("script")
+	subprotocols, _ := cmd.Flags().GetStringArray("subprotocol")
+	pingInterval, _ := cmd.Flags().GetDuration("ping-interval")
+	wait, _ := cmd.Flags().GetDuration("wait")
+
+	if pingInterval < 0 || wait < 0 {
+		return structs.WebSocketOptions{}, fmt.Errorf("--ping-interval and --wait cannot be negative")
+	}
+
+	var messages []structs.WebSocketMessage
+	for _, text := range send {
+		messages = append(messages, structs.WebSocketMessage{Type: structs.FrameText, Data: []byte(text)})
+	}
+
+	if script != "" {
+		scripted, err := utils.ParseWebSocketScript(script)
+		if err != nil {
+			return structs.WebSocketOptions{}, err
+		}
+		messages = append(messages, scripted...)
+	}
+
+	return structs.WebSocketOptions{
+		Subprotocols: subprotocols,
+		Messages:     messages,
+		Interactive:  len(send) == 0 && script == "",
+		PingInterval: pingInterval,
+		Wait:         wait,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// Collection is a YAML file describing a multi-step API flow.
+type Collection struct {
+	Name  string `yaml:"name"`
+	Vars  Vars   `yaml:"vars"`
+	Steps []Step `yaml:"steps"`
+
+	Path string `yaml:"-"`
+}
+
+// Vars keeps the vars mapping in file order, so each may use the ones above it.
+type Vars []Variable
+
+type Variable struct {
+	Name  string
+	Value string
+}
+
+func (v *Vars) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: vars must be a mapping of names to values", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value string
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return fmt.Errorf("vars.%s: %w", node.Content[i].Value, err)
+		}
+		*v = append(*v, Variable{Name: node.Content[i].Value, Value: value})
+	}
+	return nil
+}
+
+type Step struct {
+	Name     string            `yaml:"name"`
+	Method   string            `yaml:"method"`
+	URL      string            `yaml:"url"`
+	Headers  map[string]string `yaml:"headers"`
+	Body     string            `yaml:"body"`
+	BodyFile string            `yaml:"body_file"`
+	// Capture maps variable names to sources: a JSONPath into the body
+	// ($.data.id), "header <Name>" or "status".
+	Capture map[string]string `yaml:"capture"`
+}
+
+func Load(path string) (*Collection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+
+	var c Collection
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid collection %s: %w", path, err)
+	}
+
+	if len(c.Steps) == 0 {
+		return nil, fmt.Errorf("invalid collection %s: no steps", path)
+	}
+
+	for i := range c.Steps {
+		step := &c.Steps[i]
+		if step.URL == "" {
+			return nil, fmt.Errorf("invalid collection %s: step %d has no url", path, i+1)
+		}
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+		step.Method = strings.ToUpper(step.Method)
+		if step.Method == "" {
+			step.Method = http.MethodGet
+		}
+		if step.Body != "" && step.BodyFile != "" {
+			return nil, fmt.Errorf("invalid collection %s: %s has both body and body_file", path, step.Name)
+		}
+		if step.BodyFile != "" && !filepath.IsAbs(step.BodyFile) {
+			step.BodyFile = filepath.Join(filepath.Dir(path), step.BodyFile)
+		}
+		for name, source := range step.Capture {
+			if err := validateSource(source); err != nil {
+				return nil, fmt.Errorf("invalid collection %s: %s: capture %s: %w", path, step.Name, name, err)
+			}
+		}
+	}
+
+	c.Path = path
+	return &c, nil
+}
+
+func validateSource(source string) error {
+	source = strings.TrimSpace(source)
+	switch {
+	case source == "status":
+		return nil
+	case strings.HasPrefix(source, "header "):
+		if strings.TrimSpace(strings.TrimPrefix(source, "header ")) == "" {
+			return errors.New("missing header name")
+		}
+		return nil
+	case strings.HasPrefix(source, "$"):
+		_, err := jsonpath.Parse(source)
+		return err
+	}
+	return fmt.Errorf("unknown source %q: use a JSONPath ($.id), 'header <Name>' or 'status'", source)
+}
+
+// CaptureFrom extracts the step's variables from a response, in name order.
+func (s Step) CaptureFrom(resp *http.Response, body []byte) (map[string]string, error) {
+	captured := map[string]string{}
+
+	names := make([]string, 0, len(s.Capture))
+	for name := range s.Capture {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := capture(strings.TrimSpace(s.Capture[name]), resp, body)
+		if err != nil {
+			return captured, fmt.Errorf("capture %s: %w", name, err)
+		}
+		captured[name] = value
+	}
+
+	return captured, nil
+}
+
+func capture(source string, resp *http.Response, body []byte) (string, error) {
+	if source == "status" {
+		return strconv.Itoa(resp.StatusCode), nil
+	}
+
+	if header, ok := strings.CutPrefix(source, "header "); ok {
+		header = strings.TrimSpace(header)
+		values := resp.Header.Values(header)
+		if len(values) == 0 {
+			return "", fmt.Errorf("response has no %s header", header)
+		}
+		return values[0], nil
+	}
+
+	path, err := jsonpath.Parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := path.LookupJSON(body)
+	if err != nil {
+		return "", err
+	}
+	return jsonpath.Format(value), nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const EnvConfigPath = "CHARM_CONFIG"
+
+type Config struct {
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]*Profile `yaml:"profiles"`
+}
+
+type Profile struct {
+	BaseURL string            `yaml:"base_url"`
+	Headers map[string]string `yaml:"headers"`
+
+	Bearer string `yaml:"bearer"`
+	Basic  string `yaml:"basic"`
+
+	Timeout        string `yaml:"timeout"`
+	ConnectTimeout string `yaml:"connect_timeout"`
+	MaxTime        string `yaml:"max_time"`
+	Retry          int    `yaml:"retry"`
+
+	Proxy     string `yaml:"proxy"`
+	ProxyUser string `yaml:"proxy_user"`
+	NoProxy   string `yaml:"noproxy"`
+
+	TLS TLSProfile `yaml:"tls"`
+}
+
+type TLSProfile struct {
+	CACert       string `yaml:"cacert"`
+	Cert         string `yaml:"cert"`
+	Key          string `yaml:"key"`
+	CertPassword string `yaml:"cert_password"`
+	Insecure     bool   `yaml:"insecure"`
+	Min          string `yaml:"min"`
+	Max          string `yaml:"max"`
+	ServerName   string `yaml:"servername"`
+}
+
+// DefaultPath returns $CHARM_CONFIG or <user config dir>/charm/config.yaml.
+func DefaultPath() string {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "charm", "config.yaml")
+}
+
+// Load reads the config file. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	cfg := &Config{Profiles: map[string]*Profile{}}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+	return cfg, nil
+}
+
+// Profile returns the named profile, or the default one when name is empty.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FlagValues maps the profile settings to the command flags they provide defaults for.
+func (p *Profile) FlagValues() map[string]string {
+	values := map[string]string{
+		"bearer":          p.Bearer,
+		"basic":           p.Basic,
+		"timeout":         p.Timeout,
+		"connect-timeout": p.ConnectTimeout,
+		"max-time":        p.MaxTime,
+		"proxy":           p.Proxy,
+		"proxy-user":      p.ProxyUser,
+		"noproxy":         p.NoProxy,
+		"cacert":          expandHome(p.TLS.CACert),
+		"cert":            expandHome(p.TLS.Cert),
+		"key":             expandHome(p.TLS.Key),
+		"cert-password":   p.TLS.CertPassword,
+		"tls-min":         p.TLS.Min,
+		"tls-max":         p.TLS.Max,
+		"servername":      p.TLS.ServerName,
+	}
+
+	if p.Retry > 0 {
+		values["retry"] = strconv.Itoa(p.Retry)
+	}
+	if p.TLS.Insecure {
+		values["insecure"] = "true"
+	}
+
+	for name, value := range values {
+		if value == "" {
+			delete(values, name)
+		}
+	}
+	return values
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,218 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IntrospectionQuery asks for every type of the schema with its fields,
+// arguments, input fields, enum values and union members.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) {
+        name
+        args { name type { ...TypeRef } }
+        type { ...TypeRef }
+        isDeprecated
+      }
+      inputFields { name type { ...TypeRef } }
+      interfaces { ...TypeRef }
+      enumValues(includeDeprecated: true) { name isDeprecated }
+      possibleTypes { ...TypeRef }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}`
+
+// Request is the JSON envelope POSTed to a GraphQL endpoint.
+type Request struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// Envelope encodes the request as the JSON body of the HTTP request.
+func (r Request) Envelope() (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// ParseVariables decodes --variables, which must be a JSON object.
+func ParseVariables(text string) (map[string]any, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	var variables map[string]any
+	if err := decoder.Decode(&variables); err != nil {
+		return nil, fmt.Errorf("invalid --variables: must be a JSON object: %w", err)
+	}
+	return variables, nil
+}
+
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error is one entry of the "errors" list of a GraphQL response.
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations"`
+	Path       []any          `json:"path"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+// PathString renders a response path like user.friends[0].name.
+func (e Error) PathString() string {
+	var b strings.Builder
+	for _, segment := range e.Path {
+		switch value := segment.(type) {
+		case float64:
+			b.WriteString("[" + strconv.Itoa(int(value)) + "]")
+		default:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			fmt.Fprint(&b, value)
+		}
+	}
+	return b.String()
+}
+
+func (e Error) LocationsString() string {
+	locations := make([]string, len(e.Locations))
+	for i, location := range e.Locations {
+		locations[i] = fmt.Sprintf("%d:%d", location.Line, location.Column)
+	}
+	return strings.Join(locations, ", ")
+}
+
+// Response separates the data and errors of a GraphQL response.
+type Response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []Error         `json:"errors"`
+}
+
+// ParseResponse decodes a response body; it fails for bodies that are not
+// GraphQL responses, such as HTML error pages.
+func ParseResponse(body []byte) (*Response, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("not a GraphQL response: %w", err)
+	}
+
+	_, hasData := fields["data"]
+	_, hasErrors := fields["errors"]
+	if !hasData && !hasErrors {
+		return nil, fmt.Errorf("not a GraphQL response: no data or errors field")
+	}
+
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid GraphQL response: %w", err)
+	}
+	return &resp, nil
+}
+
+// TypeRef is a possibly wrapped type reference, e.g. [User!]!.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+func (t TypeRef) String() string {
+	switch {
+	case t.Kind == "NON_NULL" && t.OfType != nil:
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST" && t.OfType != nil:
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+type InputValue struct {
+	Name string  `json:"name"`
+	Type TypeRef `json:"type"`
+}
+
+type Field struct {
+	Name         string       `json:"name"`
+	Args         []InputValue `json:"args"`
+	Type         TypeRef      `json:"type"`
+	IsDeprecated bool         `json:"isDeprecated"`
+}
+
+type EnumValue struct {
+	Name         string `json:"name"`
+	IsDeprecated bool   `json:"isDeprecated"`
+}
+
+type Type struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+type namedType struct {
+	Name string `json:"name"`
+}
+
+type Schema struct {
+	QueryType        *namedType `json:"queryType"`
+	MutationType     *namedType `json:"mutationType"`
+	SubscriptionType *namedType `json:"subscriptionType"`
+	Types            []Type     `json:"types"`
+}
+
+// RootTypes returns the names of the query, mutation and subscription types.
+func (s *Schema) RootTypes() []string {
+	var names []string
+	for _, root := range []*namedType{s.QueryType, s.MutationType, s.SubscriptionType} {
+		if root != nil && root.Name != "" {
+			names = append(names, root.Name)
+		}
+	}
+	return names
+}
+
+// ParseSchema extracts the schema from the data of an introspection query.
+func ParseSchema(data json.RawMessage) (*Schema, error) {
+	var result struct {
+		Schema *Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid introspection result: %w", err)
+	}
+	if result.Schema == nil {
+		return nil, fmt.Errorf("invalid introspection result: no __schema field")
+	}
+	return result.Schema, nil
+}
@@ -11,56 +11,37 @@ func MakeRequest(opts structs.RequestOptions) (*http.Response, error) {
 	return utils.DoRequest(opts)
 }
 
-func Get(url, bearer, basic, contentType string) (*http.Response, error) {
-	return MakeRequest(structs.RequestOptions{
-		Method:      "GET",
-		URL:         url,
-		Bearer:      bearer,
-		Basic:       basic,
-		ContentType: contentType,
-	})
+func Get(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "GET"
+	return MakeRequest(opts)
 }
 
-func Post(url, bearer, basic, contentType, data string) (*http.Response, error) {
-	return MakeRequest(structs.RequestOptions{
-		Method:      "POST",
-		URL:         url,
-		Bearer:      bearer,
-		Basic:       basic,
-		ContentType: contentType,
-		Data:        data,
-	})
+func Post(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "POST"
+	return MakeRequest(opts)
 }
 
-func Put(url, bearer, basic, contentType, data string) (*http.Response, error) {
-	return MakeRequest(structs.RequestOptions{
-		Method:      "PUT",
-		URL:         url,
-		Bearer:      bearer,
-		Basic:       basic,
-		ContentType: contentType,
-		Data:        data,
-	})
+func Put(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "PUT"
+	return MakeRequest(opts)
 }
 
-func Patch(url, bearer, basic, contentType, data string) (*http.Response, error) {
-	return MakeRequest(structs.RequestOptions{
-		Method:      "PATCH",
-		URL:         url,
-		Bearer:      bearer,
-		Basic:       basic,
-		ContentType: contentType,
-		Data:        data,
-	})
+func Patch(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "PATCH"
+	return MakeRequest(opts)
 }
 
-func Delete(url, bearer, basic, contentType, data string) (*http.Response, error) {
-	return MakeRequest(structs.RequestOptions{
-		Method:      "DELETE",
-		URL:         url,
-		Bearer:      bearer,
-		Basic:       basic,
-		ContentType: contentType,
-		Data:        data,
-	})
+func Delete(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "DELETE"
+	return MakeRequest(opts)
+}
+
+func Head(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "HEAD"
+	return MakeRequest(opts)
+}
+
+func Options(opts structs.RequestOptions) (*http.Response, error) {
+	opts.Method = "OPTIONS"
+	return MakeRequest(opts)
 }
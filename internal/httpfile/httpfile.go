@@ -0,0 +1,215 @@
+package httpfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	namePattern     = regexp.MustCompile(`^(?:#|//)\s*@name\s+(\S+)\s*$`)
+	variablePattern = regexp.MustCompile(`^@([A-Za-z_][\w.-]*)\s*=\s*(.*)$`)
+	methodPattern   = regexp.MustCompile(`^[A-Z]+$`)
+	versionPattern  = regexp.MustCompile(`\s+HTTP/[\d.]+$`)
+)
+
+// Variable is an "@name = value" declaration; values may reference
+// variables declared before them.
+type Variable struct {
+	Name  string
+	Value string
+}
+
+type Request struct {
+	Name    string
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    string
+	// BodyFile is set by a "< path" body line, relative to the .http file.
+	BodyFile string
+	// ExpandBodyFile is set by "<@ path": the file content gets {{var}} substitution.
+	ExpandBodyFile bool
+	Line           int
+}
+
+// Label names the request in listings: its @name, or "METHOD URL".
+func (r Request) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Method + " " + r.URL
+}
+
+type File struct {
+	Path      string
+	Variables []Variable
+	Requests  []Request
+}
+
+func ParseFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request file: %w", err)
+	}
+	defer f.Close()
+
+	file, err := Parse(f, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	file.Path = path
+	return file, nil
+}
+
+// Parse reads the VS Code REST Client format: requests separated by ###,
+// each made of comments, a request line, headers, a blank line and a body.
+// Relative "< file" bodies are resolved against dir.
+func Parse(r io.Reader, dir string) (*File, error) {
+	file := &File{}
+	parser := &blockParser{dir: dir, file: file}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, "###") {
+			parser.flush()
+			continue
+		}
+
+		if err := parser.add(line, lineNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	parser.flush()
+
+	if len(file.Requests) == 0 {
+		return nil, fmt.Errorf("no requests found")
+	}
+	return file, nil
+}
+
+type section int
+
+const (
+	sectionPreamble section = iota
+	sectionHeaders
+	sectionBody
+)
+
+type blockParser struct {
+	dir     string
+	file    *File
+	section section
+	request *Request
+	name    string
+	body    []string
+}
+
+func (p *blockParser) add(line string, lineNumber int) error {
+	trimmed := strings.TrimSpace(line)
+
+	switch p.section {
+	case sectionPreamble:
+		if trimmed == "" {
+			return nil
+		}
+		if match := namePattern.FindStringSubmatch(trimmed); match != nil {
+			p.name = match[1]
+			return nil
+		}
+		if isComment(trimmed) {
+			return nil
+		}
+		if match := variablePattern.FindStringSubmatch(trimmed); match != nil {
+			p.file.Variables = append(p.file.Variables, Variable{Name: match[1], Value: strings.TrimSpace(match[2])})
+			return nil
+		}
+
+		p.request = parseRequestLine(trimmed, lineNumber)
+		p.request.Name = p.name
+		p.section = sectionHeaders
+
+	case sectionHeaders:
+		if trimmed == "" {
+			p.section = sectionBody
+			return nil
+		}
+		if isComment(trimmed) {
+			return nil
+		}
+		if strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&") {
+			p.request.URL += trimmed
+			return nil
+		}
+
+		name, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("line %d: invalid header %q: expected format 'Name: Value'", lineNumber, trimmed)
+		}
+		p.request.Headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+
+	case sectionBody:
+		p.body = append(p.body, line)
+	}
+
+	return nil
+}
+
+func (p *blockParser) flush() {
+	if p.request != nil {
+		p.setBody(strings.TrimRight(strings.Join(p.body, "\n"), " \t\n"))
+		p.file.Requests = append(p.file.Requests, *p.request)
+	}
+
+	p.section, p.request, p.name, p.body = sectionPreamble, nil, "", nil
+}
+
+func (p *blockParser) setBody(body string) {
+	path, expand := "", false
+	switch {
+	case strings.HasPrefix(body, "<@ "):
+		path, expand = strings.TrimSpace(body[3:]), true
+	case strings.HasPrefix(body, "< "):
+		path = strings.TrimSpace(body[2:])
+	default:
+		p.request.Body = body
+		return
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, path)
+	}
+	p.request.BodyFile, p.request.ExpandBodyFile = path, expand
+}
+
+// parseRequestLine accepts "METHOD URL [HTTP/1.1]" or a bare URL (GET).
+func parseRequestLine(line string, lineNumber int) *Request {
+	request := &Request{Method: http.MethodGet, Headers: http.Header{}, Line: lineNumber}
+
+	line = versionPattern.ReplaceAllString(line, "")
+	if method, rest, ok := strings.Cut(line, " "); ok && methodPattern.MatchString(method) {
+		request.Method, line = method, strings.TrimSpace(rest)
+	}
+
+	request.URL = line
+	return request
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//")
+}
@@ -0,0 +1,165 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// segment is one step of a path: an object key, or an array index when
+// isIndex is set (negative indexes count from the end).
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (s segment) String() string {
+	if s.isIndex {
+		return fmt.Sprintf("[%d]", s.index)
+	}
+	return "." + s.key
+}
+
+// Path is a compiled JSONPath subset: $, .key, ['key'], ["key"] and [index].
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+func Parse(expr string) (*Path, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", expr)
+	}
+
+	path := &Path{expr: expr}
+	rest := expr[1:]
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty key", expr)
+			}
+			path.segments = append(path.segments, segment{key: key})
+			rest = rest[end+1:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ]", expr)
+			}
+			seg, err := parseBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+			}
+			path.segments = append(path.segments, seg)
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+func parseBracket(content string) (segment, error) {
+	content = strings.TrimSpace(content)
+
+	if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+		return segment{key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return segment{}, fmt.Errorf("expected an index or a quoted key, got [%s]", content)
+	}
+	return segment{index: index, isIndex: true}, nil
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Lookup walks the decoded JSON value (as produced by encoding/json).
+func (p *Path) Lookup(data any) (any, error) {
+	current := data
+	walked := "$"
+
+	for _, seg := range p.segments {
+		switch value := current.(type) {
+		case map[string]any:
+			if seg.isIndex {
+				return nil, fmt.Errorf("%s is an object, cannot index it with %s", walked, seg)
+			}
+			next, ok := value[seg.key]
+			if !ok {
+				return nil, fmt.Errorf("%s%s not found", walked, seg)
+			}
+			current = next
+
+		case []any:
+			if !seg.isIndex {
+				return nil, fmt.Errorf("%s is an array, cannot read key %q", walked, seg.key)
+			}
+			index := seg.index
+			if index < 0 {
+				index += len(value)
+			}
+			if index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("%s%s out of range (length %d)", walked, seg, len(value))
+			}
+			current = value[index]
+
+		default:
+			return nil, fmt.Errorf("%s%s not found: %s is not an object or array", walked, seg, walked)
+		}
+
+		walked += seg.String()
+	}
+
+	return current, nil
+}
+
+// LookupJSON decodes body and looks the path up in it. Numbers are kept as
+// written, so ids beyond float64 precision are captured intact.
+func (p *Path) LookupJSON(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("response body is not valid JSON: unexpected data after the top-level value")
+	}
+	return p.Lookup(data)
+}
+
+// Format renders a looked up value as text: strings and numbers as written,
+// everything else as compact JSON.
+func Format(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
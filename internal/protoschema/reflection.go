@@ -0,0 +1,190 @@
+package protoschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionMethods are tried in order: v1alpha is still the only version
+// many servers expose, and its messages are identical to v1 on the wire.
+var reflectionMethods = []string{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+}
+
+var reflectionStreamDesc = &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+
+func isReflectionService(name string) bool {
+	return strings.HasPrefix(name, "grpc.reflection.")
+}
+
+// FromReflection downloads the schema through the server reflection service:
+// the file declaring service and its dependencies, or every service when
+// service is empty.
+func FromReflection(ctx context.Context, conn grpc.ClientConnInterface, service string) (*Schema, error) {
+	client, services, err := openReflection(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	defer client.stream.CloseSend()
+
+	symbols := services
+	if service != "" {
+		symbols = []string{service}
+	}
+
+	for _, symbol := range symbols {
+		if isReflectionService(symbol) {
+			continue
+		}
+		if err := client.fetch(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+		}); err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil, fmt.Errorf("service %s not found on the server (use --list to see the available services)", symbol)
+			}
+			return nil, fmt.Errorf("failed to resolve %s: %w", symbol, err)
+		}
+	}
+
+	if err := client.fetchDependencies(); err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range client.files {
+		set.File = append(set.File, file)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptors from server reflection: %w", err)
+	}
+	return newSchema(files), nil
+}
+
+type reflectionClient struct {
+	stream grpc.ClientStream
+	files  map[string]*descriptorpb.FileDescriptorProto
+}
+
+// openReflection finds a reflection version the server implements; the first
+// exchange, listing the services, tells whether the method exists.
+func openReflection(ctx context.Context, conn grpc.ClientConnInterface) (*reflectionClient, []string, error) {
+	var err error
+	for _, method := range reflectionMethods {
+		var stream grpc.ClientStream
+		stream, err = conn.NewStream(ctx, reflectionStreamDesc, method)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		client := &reflectionClient{stream: stream, files: map[string]*descriptorpb.FileDescriptorProto{}}
+		var resp *reflectionpb.ServerReflectionResponse
+		resp, err = client.exchange(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+		})
+		if status.Code(err) == codes.Unimplemented {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var services []string
+		for _, service := range resp.GetListServicesResponse().GetService() {
+			services = append(services, service.GetName())
+		}
+		return client, services, nil
+	}
+
+	return nil, nil, fmt.Errorf("server reflection is not available (use --proto to load the schema from .proto files): %w", err)
+}
+
+func (c *reflectionClient) exchange(req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	// io.EOF means the stream was closed; the real status surfaces on the
+	// receiving side.
+	if err := c.stream.SendMsg(req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	resp := &reflectionpb.ServerReflectionResponse{}
+	if err := c.stream.RecvMsg(resp); err != nil {
+		return nil, err
+	}
+	if failure := resp.GetErrorResponse(); failure != nil {
+		return nil, status.Error(codes.Code(failure.GetErrorCode()), failure.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+// fetch stores the files of a response; servers may send a file's
+// dependencies along with it.
+func (c *reflectionClient) fetch(req *reflectionpb.ServerReflectionRequest) error {
+	resp, err := c.exchange(req)
+	if err != nil {
+		return err
+	}
+
+	for _, data := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(data, file); err != nil {
+			return fmt.Errorf("invalid file descriptor from server reflection: %w", err)
+		}
+		c.files[file.GetName()] = file
+	}
+	return nil
+}
+
+// fetchDependencies asks for the imports not received yet, taking the
+// well-known types from the local registry when the server omits them.
+func (c *reflectionClient) fetchDependencies() error {
+	for {
+		missing := c.missingDependencies()
+		if len(missing) == 0 {
+			return nil
+		}
+
+		for _, name := range missing {
+			err := c.fetch(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if _, ok := c.files[name]; ok {
+				continue
+			}
+			if local, localErr := protoregistry.GlobalFiles.FindFileByPath(name); localErr == nil {
+				c.files[name] = protodesc.ToFileDescriptorProto(local)
+				continue
+			}
+			if err == nil {
+				err = fmt.Errorf("not returned by the server")
+			}
+			return fmt.Errorf("failed to resolve import %s: %w", name, err)
+		}
+	}
+}
+
+func (c *reflectionClient) missingDependencies() []string {
+	var missing []string
+	seen := map[string]bool{}
+	for _, file := range c.files {
+		for _, dependency := range file.GetDependency() {
+			if _, ok := c.files[dependency]; !ok && !seen[dependency] {
+				seen[dependency] = true
+				missing = append(missing, dependency)
+			}
+		}
+	}
+	return missing
+}
@@ -0,0 +1,188 @@
+package protoschema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/bufbuild/protocompile"
+	// Registers the google.rpc error details that servers attach to statuses.
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Schema holds the services and messages used to transcode JSON to
+// protobuf and back, whether they came from server reflection or .proto files.
+type Schema struct {
+	files *protoregistry.Files
+	types *dynamicpb.Types
+}
+
+func newSchema(files *protoregistry.Files) *Schema {
+	return &Schema{files: files, types: dynamicpb.NewTypes(files)}
+}
+
+// Resolver finds message types for protojson, including those packed in
+// google.protobuf.Any.
+type Resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// Resolver looks in the schema first, then among the types compiled into
+// charm, such as the google.rpc error details.
+func (s *Schema) Resolver() Resolver {
+	return resolver{s.types}
+}
+
+type resolver struct {
+	*dynamicpb.Types
+}
+
+func (r resolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	if messageType, err := r.Types.FindMessageByName(name); err == nil {
+		return messageType, nil
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(name)
+}
+
+func (r resolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	if messageType, err := r.Types.FindMessageByURL(url); err == nil {
+		return messageType, nil
+	}
+	return protoregistry.GlobalTypes.FindMessageByURL(url)
+}
+
+// ParseMethodName splits package.Service/Method; package.Service.Method and
+// a leading slash are accepted too.
+func ParseMethodName(name string) (string, string, error) {
+	name = strings.TrimPrefix(name, "/")
+
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		dot := strings.LastIndex(name, ".")
+		if dot < 0 {
+			return "", "", fmt.Errorf("invalid method %q: use package.Service/Method", name)
+		}
+		service, method = name[:dot], name[dot+1:]
+	}
+
+	if service == "" || method == "" || strings.Contains(method, "/") {
+		return "", "", fmt.Errorf("invalid method %q: use package.Service/Method", name)
+	}
+	return service, method, nil
+}
+
+// FindMethod looks up package.Service/Method.
+func (s *Schema) FindMethod(name string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := ParseMethodName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := s.files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found (use --list to see the available services)", serviceName)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", serviceName)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		var names []string
+		for i := 0; i < service.Methods().Len(); i++ {
+			names = append(names, string(service.Methods().Get(i).Name()))
+		}
+		return nil, fmt.Errorf("method %s not found in %s (available: %s)", methodName, serviceName, strings.Join(names, ", "))
+	}
+	return method, nil
+}
+
+// Services lists every service of the schema, sorted by name, except the
+// reflection service itself.
+func (s *Schema) Services() []structs.GRPCService {
+	var services []structs.GRPCService
+	s.files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		for i := 0; i < file.Services().Len(); i++ {
+			service := file.Services().Get(i)
+			if isReflectionService(string(service.FullName())) {
+				continue
+			}
+
+			entry := structs.GRPCService{Name: string(service.FullName())}
+			for j := 0; j < service.Methods().Len(); j++ {
+				entry.Methods = append(entry.Methods, Method(service.Methods().Get(j)))
+			}
+			services = append(services, entry)
+		}
+		return true
+	})
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
+
+// Method describes a method for display.
+func Method(method protoreflect.MethodDescriptor) structs.GRPCMethod {
+	return structs.GRPCMethod{
+		Name:            string(method.Name()),
+		Input:           string(method.Input().FullName()),
+		Output:          string(method.Output().FullName()),
+		ClientStreaming: method.IsStreamingClient(),
+		ServerStreaming: method.IsStreamingServer(),
+	}
+}
+
+// MethodPath is the HTTP/2 path of a method: /package.Service/Method.
+func MethodPath(method protoreflect.MethodDescriptor) string {
+	return "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+}
+
+// FromProtoFiles compiles .proto files, resolving their imports against the
+// import paths (the current directory by default) and the well-known types.
+func FromProtoFiles(ctx context.Context, paths, importPaths []string) (*Schema, error) {
+	if len(importPaths) == 0 {
+		importPaths = []string{"."}
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+	}
+	compiled, err := compiler.Compile(ctx, paths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile .proto files: %w", err)
+	}
+
+	files := new(protoregistry.Files)
+	for _, file := range compiled {
+		if err := registerFile(files, file); err != nil {
+			return nil, err
+		}
+	}
+	return newSchema(files), nil
+}
+
+// registerFile adds a file after its imports, which the registry requires.
+func registerFile(files *protoregistry.Files, file protoreflect.FileDescriptor) error {
+	if _, err := files.FindFileByPath(file.Path()); err == nil {
+		return nil
+	}
+
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if err := registerFile(files, imports.Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+
+	if err := files.RegisterFile(file); err != nil {
+		return fmt.Errorf("failed to register %s: %w", file.Path(), err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package structs
+
+import "time"
+
+// GRPCOptions holds the settings specific to `charm grpc`; metadata, auth,
+// TLS and timeouts come from RequestOptions like for any other request.
+type GRPCOptions struct {
+	// Method is package.Service/Method; empty with List.
+	Method string
+	// ProtoFiles replace server reflection as the source of the schema.
+	ProtoFiles  []string
+	ImportPaths []string
+	Plaintext   bool
+	// Web calls the method over gRPC-Web (HTTP/1.1) instead of HTTP/2.
+	Web  bool
+	List bool
+}
+
+// GRPCService is one service of the schema, as listed by --list.
+type GRPCService struct {
+	Name    string
+	Methods []GRPCMethod
+}
+
+type GRPCMethod struct {
+	Name            string
+	Input           string
+	Output          string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// Kind names the call style: unary, client/server streaming or bidi.
+func (m GRPCMethod) Kind() string {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return "bidi streaming"
+	case m.ClientStreaming:
+		return "client streaming"
+	case m.ServerStreaming:
+		return "server streaming"
+	}
+	return "unary"
+}
+
+// GRPCCall is the outcome of an invocation: the messages exchanged, the
+// metadata on both sides and the final status.
+type GRPCCall struct {
+	Target string
+	Method GRPCMethod
+	Path   string
+	Web    bool
+	Secure bool
+	// Live is set when responses are displayed as they arrive, which
+	// server streaming calls over HTTP/2 do.
+	Live     bool
+	Metadata map[string][]string
+	// Requests and Responses are the messages encoded as JSON.
+	Requests  [][]byte
+	Responses [][]byte
+	Headers   map[string][]string
+	Trailers  map[string][]string
+
+	Code     int
+	CodeName string
+	Message  string
+	// Details are the google.rpc.Status details encoded as JSON.
+	Details [][]byte
+
+	Timing     *TimingInfo
+	TotalTime  time.Duration
+	Assertions []Assertion
+	ShowTLS    bool
+	Output     string
+}
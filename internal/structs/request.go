@@ -2,23 +2,123 @@ package structs
 
 import (
 	"net/http"
+	"net/url"
 	"time"
 )
 
+const (
+	OutputPretty = "pretty"
+	OutputJSON   = "json"
+	OutputRaw    = "raw"
+)
+
+var OutputModes = []string{OutputPretty, OutputJSON, OutputRaw}
+
 type HTTPMethod struct {
-	Name           string
-	HasBody        bool
-	HTTPFunc       func(string, string, string, string, string) (*http.Response, error)
-	HTTPFuncNoBody func(string, string, string, string) (*http.Response, error)
+	Name     string
+	HasBody  bool
+	HTTPFunc func(RequestOptions) (*http.Response, error)
 }
 
 type RequestOptions struct {
 	Method      string
 	URL         string
+	BaseURL     string
 	Bearer      string
 	Basic       string
 	ContentType string
+	Headers     http.Header
+	Query       url.Values
 	Data        string
+	DataFile    string
+	Form        []FormField
+	Output      string
+
+	Timeout        time.Duration
+	ConnectTimeout time.Duration
+	MaxTime        time.Duration
+
+	Retry      int
+	RetryOn    []string
+	RetryDelay time.Duration
+
+	NoFollow     bool
+	MaxRedirects int
+	KeepMethod   bool
+	KeepAuth     bool
+
+	Proxy   *url.URL
+	NoProxy []string
+
+	TLS     TLSOptions
+	TLSInfo bool
+
+	Expect Expectations
+	Filter string
+
+	OutputFile string
+	RemoteName bool
+	Resume     bool
+
+	Stream    bool
+	Reconnect bool
+	// KeepBody reads event streams into memory like any other response, for
+	// callers that inspect the body afterwards.
+	KeepBody bool
+
+	GraphQL *GraphQLOptions
+}
+
+// GraphQLOptions marks a request sent by `charm graphql`, whose response is
+// shown as separate data and errors.
+type GraphQLOptions struct {
+	Operation     string
+	Introspection bool
+}
+
+// Expectations are the --fail and --expect-* checks run on the response.
+type Expectations struct {
+	Fail    bool
+	Status  []string
+	Headers []HeaderExpectation
+	// JSON holds --expect-json jq expressions, each passing when it yields true.
+	JSON    []string
+	MaxTime time.Duration
+	// NoGraphQLErrors makes a GraphQL response with errors fail, even with
+	// a 200 status.
+	NoGraphQLErrors bool
+}
+
+// HeaderExpectation checks that a header is present and, when Value is set,
+// that it contains Value.
+type HeaderExpectation struct {
+	Name  string
+	Value string
+}
+
+type TLSOptions struct {
+	CACert       string
+	Cert         string
+	Key          string
+	CertPassword string
+	Insecure     bool
+	MinVersion   uint16
+	MaxVersion   uint16
+	ServerName   string
+	Pins         [][]byte
+}
+
+type FormField struct {
+	Name        string
+	Value       string
+	FilePath    string
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+func (f FormField) IsFile() bool {
+	return f.FilePath != ""
 }
 
 type Display struct {
@@ -28,12 +128,29 @@ type Display struct {
 	Basic       string
 	ContentType string
 	Data        string
+	DataFile    string
+	Form        []FormField
+	DataSize    int64
 	Request     *http.Request
 	Response    *http.Response
 	Body        []byte
 	AuthHeader  string
 	TotalTime   time.Duration
 	Timing      *TimingInfo
+	Output      string
+	Failure     *Failure
+	Attempts    []Attempt
+	Redirects   []Redirect
+	Proxy       *url.URL
+	ShowTLS     bool
+	Assertions  []Assertion
+	// Filter and FilterResults hold the --filter expression and its outputs,
+	// rendered in place of the response body.
+	Filter        string
+	FilterResults []any
+	Saved         *SavedFile
+	Stream        *StreamSummary
+	GraphQL       *GraphQLOptions
 }
 
 func NewDisplay(method, url string) *Display {
@@ -56,6 +173,13 @@ func (d *Display) WithContent(contentType, data string) *Display {
 	return d
 }
 
+func (d *Display) WithBodySource(dataFile string, form []FormField, size int64) *Display {
+	d.DataFile = dataFile
+	d.Form = form
+	d.DataSize = size
+	return d
+}
+
 func (d *Display) WithHTTP(req *http.Request, resp *http.Response, body []byte) *Display {
 	d.Request = req
 	d.Response = resp
@@ -63,6 +187,62 @@ func (d *Display) WithHTTP(req *http.Request, resp *http.Response, body []byte)
 	return d
 }
 
+func (d *Display) WithAssertions(assertions []Assertion) *Display {
+	d.Assertions = assertions
+	return d
+}
+
+func (d *Display) WithFilter(filter string, results []any) *Display {
+	d.Filter = filter
+	d.FilterResults = results
+	return d
+}
+
+func (d *Display) WithSavedFile(saved *SavedFile) *Display {
+	d.Saved = saved
+	return d
+}
+
+func (d *Display) WithStream(stream *StreamSummary) *Display {
+	d.Stream = stream
+	return d
+}
+
+func (d *Display) WithGraphQL(graphQL *GraphQLOptions) *Display {
+	d.GraphQL = graphQL
+	return d
+}
+
+func (d *Display) WithOutput(output string) *Display {
+	d.Output = output
+	return d
+}
+
+func (d *Display) WithAttempts(attempts []Attempt) *Display {
+	d.Attempts = attempts
+	return d
+}
+
+func (d *Display) WithRedirects(redirects []Redirect) *Display {
+	d.Redirects = redirects
+	return d
+}
+
+func (d *Display) WithProxy(proxy *url.URL) *Display {
+	d.Proxy = proxy
+	return d
+}
+
+func (d *Display) WithTLSInfo(show bool) *Display {
+	d.ShowTLS = show
+	return d
+}
+
+func (d *Display) WithFailure(failure *Failure) *Display {
+	d.Failure = failure
+	return d
+}
+
 func (d *Display) WithTiming(totalTime time.Duration, timing *TimingInfo) *Display {
 	d.TotalTime = totalTime
 	d.Timing = timing
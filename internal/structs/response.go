@@ -1,6 +1,9 @@
 package structs
 
-import "time"
+import (
+	"crypto/tls"
+	"time"
+)
 
 type Response struct {
 	StatusCode    int
@@ -12,14 +15,138 @@ type Response struct {
 }
 
 type TimingInfo struct {
-	DNSStart      time.Time
-	DNSDone       time.Time
-	ConnectStart  time.Time
-	ConnectDone   time.Time
-	TLSStart      time.Time
-	TLSDone       time.Time
-	RequestStart  time.Time
-	RequestDone   time.Time
-	ResponseStart time.Time
-	ResponseDone  time.Time
+	DNSStart     time.Time
+	DNSDone      time.Time
+	ConnectStart time.Time
+	ConnectDone  time.Time
+	// ProxyConnectStart/Done cover the CONNECT tunnel through an HTTP proxy.
+	ProxyConnectStart time.Time
+	ProxyConnectDone  time.Time
+	TLSStart          time.Time
+	TLSDone           time.Time
+	RequestStart      time.Time
+	RequestDone       time.Time
+	ResponseStart     time.Time
+	ResponseDone      time.Time
+	// TLS is the connection state reported by the handshake. When the server
+	// certificate is rejected only the chain it sent is kept, with
+	// HandshakeComplete false.
+	TLS *tls.ConnectionState
+}
+
+const (
+	PhaseDNS      = "DNS lookup"
+	PhaseConnect  = "TCP connect"
+	PhaseProxy    = "proxy CONNECT"
+	PhaseTLS      = "TLS handshake"
+	PhaseSending  = "sending request"
+	PhaseWaiting  = "waiting for first byte"
+	PhaseTransfer = "receiving response body"
+	PhaseRedirect = "following redirects"
+)
+
+// Phase reports the step a request was in, based on which timestamps were recorded.
+func (t *TimingInfo) Phase() string {
+	switch {
+	case !t.DNSStart.IsZero() && t.DNSDone.IsZero():
+		return PhaseDNS
+	case !t.ConnectStart.IsZero() && t.ConnectDone.IsZero():
+		return PhaseConnect
+	case !t.ProxyConnectStart.IsZero() && t.ProxyConnectDone.IsZero():
+		return PhaseProxy
+	case !t.TLSStart.IsZero() && t.TLSDone.IsZero():
+		return PhaseTLS
+	case t.ConnectDone.IsZero() && t.DNSStart.IsZero():
+		return PhaseConnect
+	case t.RequestDone.IsZero():
+		return PhaseSending
+	case t.ResponseStart.IsZero():
+		return PhaseWaiting
+	default:
+		return PhaseTransfer
+	}
+}
+
+const (
+	FailureError       = "error"
+	FailureTimeout     = "timeout"
+	FailureInterrupted = "interrupted"
+)
+
+type Failure struct {
+	Kind  string
+	Phase string
+	Limit string
+	Hint  string
+	Err   error
+}
+
+type Attempt struct {
+	Number     int
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+	RetryIn    time.Duration
+	RetryAfter bool
+}
+
+type Redirect struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Location   string
+	Duration   time.Duration
+}
+
+// SavedFile describes a response body streamed to disk with -o/-O.
+type SavedFile struct {
+	Path string
+	// Size is the final file size; ResumedFrom the bytes already on disk
+	// when a --continue download started.
+	Size        int64
+	ResumedFrom int64
+}
+
+const (
+	StreamSSE    = "sse"
+	StreamNDJSON = "ndjson"
+	StreamLines  = "lines"
+)
+
+// StreamEvent is one record of a streamed body: an SSE event, an NDJSON
+// line or a plain text line.
+type StreamEvent struct {
+	Mode    string
+	Event   string
+	ID      string
+	Data    string
+	Elapsed time.Duration
+}
+
+// StreamSummary replaces the body of a response that was rendered while it
+// arrived.
+type StreamSummary struct {
+	Mode       string
+	Events     int
+	Bytes      int64
+	Reconnects int
+	StopReason string
+}
+
+// Assertion is the outcome of one --fail or --expect-* check.
+type Assertion struct {
+	Name   string
+	Passed bool
+	Actual string
+}
+
+// StepResult is one row of a collection run summary.
+type StepResult struct {
+	Name       string
+	Method     string
+	StatusCode int
+	Duration   time.Duration
+	Captured   []string
+	Error      string
+	Skipped    bool
 }
@@ -0,0 +1,64 @@
+package structs
+
+import "time"
+
+const (
+	FrameText   = "text"
+	FrameBinary = "binary"
+	FramePing   = "ping"
+	FramePong   = "pong"
+	FrameClose  = "close"
+
+	// MessageSleep pauses a script instead of sending a frame.
+	MessageSleep = "sleep"
+)
+
+// WebSocketOptions holds the settings specific to `charm ws`; auth, headers,
+// proxy and TLS come from RequestOptions like for any other request.
+type WebSocketOptions struct {
+	Subprotocols []string
+	Messages     []WebSocketMessage
+	// Interactive reads further messages from stdin, one per line.
+	Interactive  bool
+	PingInterval time.Duration
+	// Wait is how long to keep listening once every message was sent;
+	// zero listens until the server closes or the user interrupts.
+	Wait time.Duration
+}
+
+// WebSocketMessage is one action of a script: a frame to send or a pause.
+type WebSocketMessage struct {
+	Type      string
+	Data      []byte
+	Delay     time.Duration
+	CloseCode int
+}
+
+// WebSocketFrame is a frame sent or received during a session.
+type WebSocketFrame struct {
+	Sent      bool
+	Type      string
+	Data      []byte
+	CloseCode int
+	// RTT is set on pongs that answer a ping sent by charm.
+	RTT     time.Duration
+	Elapsed time.Duration
+}
+
+// WebSocketSummary is shown when a session ends.
+type WebSocketSummary struct {
+	Sent          int
+	Received      int
+	BytesSent     int64
+	BytesReceived int64
+	Pings         int
+	Pongs         int
+	TotalRTT      time.Duration
+
+	CloseCode   int
+	CloseReason string
+	// ClosedBy is "client" or "server"; empty when the connection dropped
+	// without a close handshake.
+	ClosedBy string
+	Duration time.Duration
+}
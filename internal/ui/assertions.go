@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+)
+
+type jsonAssertion struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Actual string `json:"actual"`
+}
+
+func DisplayAssertions(assertions []structs.Assertion) {
+	displayAssertions(os.Stdout, assertions)
+}
+
+// displayAssertions draws the pass/fail panel; raw output sends it to stderr
+// so the body on stdout stays untouched.
+func displayAssertions(w io.Writer, assertions []structs.Assertion) {
+	if len(assertions) == 0 {
+		return
+	}
+
+	white := color.New(color.FgHiWhite)
+	gray := color.New(color.FgWhite)
+	green := color.New(color.FgHiGreen)
+	red := color.New(color.FgHiRed)
+
+	failed := 0
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			failed++
+		}
+	}
+
+	titleColor := green
+	if failed > 0 {
+		titleColor = red
+	}
+	titleColor.Fprintln(w, "╭─ 🧪 ASSERTIONS ─────────────────────────────────────────────────────────────╮")
+
+	for _, assertion := range assertions {
+		mark, markColor := "✓", green
+		if !assertion.Passed {
+			mark, markColor = "✗", red
+		}
+
+		actual := truncateString(assertion.Actual, 28)
+		name := truncateString(assertion.Name, boxWidth-visualLen(actual)-6)
+
+		gray.Fprint(w, "│ ")
+		markColor.Fprint(w, mark+" ")
+		white.Fprint(w, name)
+		gray.Fprint(w, strings.Repeat(" ", max(1, boxWidth-4-visualLen(name)-visualLen(actual)))+actual)
+		fmt.Fprintln(w, " │")
+	}
+
+	white.Fprintln(w, "├─────────────────────────────────────────────────────────────────────────────┤")
+
+	summary := fmt.Sprintf("%d/%d passed", len(assertions)-failed, len(assertions))
+	if failed > 0 {
+		summary = fmt.Sprintf("%d of %d failed", failed, len(assertions))
+	}
+	gray.Fprint(w, "│ ")
+	titleColor.Fprint(w, summary)
+	fmt.Fprintln(w, strings.Repeat(" ", max(0, boxWidth-1-visualLen(summary)))+"│")
+
+	white.Fprintln(w, "╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Fprintln(w)
+}
+
+func buildJSONAssertions(assertions []structs.Assertion) []jsonAssertion {
+	if len(assertions) == 0 {
+		return nil
+	}
+
+	result := make([]jsonAssertion, 0, len(assertions))
+	for _, assertion := range assertions {
+		result = append(result, jsonAssertion{
+			Name:   assertion.Name,
+			Passed: assertion.Passed,
+			Actual: assertion.Actual,
+		})
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+)
+
+type jsonAttempt struct {
+	Number     int     `json:"number"`
+	Status     int     `json:"status,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+	RetryInMs  float64 `json:"retry_in_ms,omitempty"`
+	RetryAfter bool    `json:"retry_after,omitempty"`
+}
+
+// DisplayRetry reports a failed attempt on stderr while charm waits to retry,
+// so it never mixes with json/raw output on stdout.
+func DisplayRetry(attempt structs.Attempt, maxAttempts int) {
+	yellow := color.New(color.FgYellow)
+	gray := color.New(color.FgWhite)
+
+	reason := ""
+	if attempt.RetryAfter {
+		reason = " (Retry-After)"
+	}
+
+	yellow.Fprintf(os.Stderr, "↻ Attempt %d/%d: %s", attempt.Number, maxAttempts, attemptOutcome(attempt))
+	gray.Fprintf(os.Stderr, ", retrying in %s%s\n", attempt.RetryIn.Round(time.Millisecond), reason)
+}
+
+func DisplayAttempts(attempts []structs.Attempt) {
+	if len(attempts) < 2 {
+		return
+	}
+
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+	gray := color.New(color.FgWhite)
+
+	cyan.Println("╭─ 🔁 ATTEMPTS ───────────────────────────────────────────────────────────────╮")
+
+	for _, attempt := range attempts {
+		statusColor := color.FgHiRed
+		if attempt.Error == "" {
+			statusColor = GetColorByStatus(attempt.StatusCode)
+		}
+
+		number := fmt.Sprintf("│   #%-2d ", attempt.Number)
+		outcome := truncateString(attemptOutcome(attempt), 32)
+		duration := attempt.Duration.Round(time.Millisecond).String()
+
+		retry := ""
+		if attempt.RetryIn > 0 {
+			retry = "→ wait " + attempt.RetryIn.Round(time.Millisecond).String()
+			if attempt.RetryAfter {
+				retry += " (Retry-After)"
+			}
+		}
+
+		gray.Print(number)
+		color.New(statusColor).Print(outcome + strings.Repeat(" ", max(0, 32-visualLen(outcome))))
+		white.Print(" " + duration + strings.Repeat(" ", max(0, 8-len(duration))))
+		gray.Print(" " + retry)
+		fmt.Println(strings.Repeat(" ", max(0, boxWidth+1-visualLen(number)-33-max(8, len(duration))-1-visualLen(retry))) + "│")
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+func attemptOutcome(attempt structs.Attempt) string {
+	if attempt.Error != "" {
+		return "error: " + attempt.Error
+	}
+	return fmt.Sprintf("%d %s", attempt.StatusCode, http.StatusText(attempt.StatusCode))
+}
+
+func buildJSONAttempts(attempts []structs.Attempt) []jsonAttempt {
+	if len(attempts) < 2 {
+		return nil
+	}
+
+	result := make([]jsonAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		result = append(result, jsonAttempt{
+			Number:     attempt.Number,
+			Status:     attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMs: milliseconds(attempt.Duration),
+			RetryInMs:  milliseconds(attempt.RetryIn),
+			RetryAfter: attempt.RetryAfter,
+		})
+	}
+	return result
+}
@@ -0,0 +1,525 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/tidwall/pretty"
+	"golang.org/x/net/html"
+)
+
+const (
+	bodyWidth      = boxWidth - 2
+	hexPreviewSize = 256
+	maxTableRows   = 100
+)
+
+var (
+	tagColor     = color.New(color.FgHiBlue)
+	attrColor    = color.New(color.FgHiCyan)
+	valueColor   = color.New(color.FgGreen)
+	literalColor = color.New(color.FgYellow)
+	commentColor = color.New(color.FgWhite)
+	textColor    = color.New(color.FgHiWhite)
+	headerColor  = color.New(color.FgHiCyan, color.Bold)
+
+	yamlKeyPattern = regexp.MustCompile(`^(\s*(?:-\s+)?)([^\s#:][^:#]*?):(\s+|$)(.*)$`)
+	yamlListItem   = regexp.MustCompile(`^(\s*-)(\s+)(.*)$`)
+	yamlLiteral    = regexp.MustCompile(`^(true|false|null|~|-?\d+(\.\d+)?)$`)
+)
+
+// bodyLines renders a response body for the boxed display according to its
+// Content-Type, falling back to sniffing for JSON and binary content.
+func bodyLines(contentType string, body []byte) []string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return jsonLines(body)
+	case mediaType == "text/html":
+		return htmlLines(body)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return xmlLines(body)
+	case strings.Contains(mediaType, "yaml"):
+		return yamlLines(body)
+	case mediaType == "text/csv":
+		return tableLines(body, ',')
+	case mediaType == "text/tab-separated-values":
+		return tableLines(body, '\t')
+	case mediaType == "application/x-www-form-urlencoded":
+		return formLines(body)
+	case isBinary(mediaType, body):
+		return binaryLines(mediaType, body)
+	case json.Valid(body):
+		return jsonLines(body)
+	}
+
+	return textLines(body)
+}
+
+func jsonLines(body []byte) []string {
+	return nonEmpty(SplitLines(string(pretty.Color(pretty.Pretty(body), nil))))
+}
+
+func textLines(body []byte) []string {
+	text := strings.ReplaceAll(string(body), "\t", "    ")
+	return SplitLines(strings.ReplaceAll(text, "\r\n", "\n"))
+}
+
+// xmlLines indents and colors an XML document; unparseable documents are
+// shown as text.
+func xmlLines(body []byte) []string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	var tokens []xml.Token
+	for {
+		// RawToken keeps namespace prefixes (soap:Envelope) as written.
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return textLines(body)
+		}
+		tokens = append(tokens, xml.CopyToken(token))
+	}
+
+	var lines []string
+	depth := 0
+	indent := func() string { return strings.Repeat("  ", depth) }
+
+	for i := 0; i < len(tokens); i++ {
+		switch token := tokens[i].(type) {
+		case xml.StartElement:
+			open := tagColor.Sprint("<"+qualifiedName(token.Name)) + xmlAttributes(token.Attr)
+
+			// <tag/> for empty elements, <tag>text</tag> for simple ones.
+			if i+1 < len(tokens) {
+				if _, ok := tokens[i+1].(xml.EndElement); ok {
+					lines = append(lines, indent()+open+tagColor.Sprint("/>"))
+					i++
+					continue
+				}
+			}
+			if i+2 < len(tokens) {
+				text, isText := tokens[i+1].(xml.CharData)
+				end, isEnd := tokens[i+2].(xml.EndElement)
+				if isText && isEnd && isInlineText(string(text)) {
+					lines = append(lines, indent()+open+tagColor.Sprint(">")+
+						textColor.Sprint(strings.TrimSpace(string(text)))+
+						tagColor.Sprint("</"+qualifiedName(end.Name)+">"))
+					i += 2
+					continue
+				}
+			}
+
+			lines = append(lines, indent()+open+tagColor.Sprint(">"))
+			depth++
+
+		case xml.EndElement:
+			depth = max(0, depth-1)
+			lines = append(lines, indent()+tagColor.Sprint("</"+qualifiedName(token.Name)+">"))
+
+		case xml.CharData:
+			lines = append(lines, textBlock(string(token), indent())...)
+
+		case xml.Comment:
+			lines = append(lines, indent()+commentColor.Sprint("<!--"+string(token)+"-->"))
+
+		case xml.ProcInst:
+			lines = append(lines, indent()+commentColor.Sprintf("<?%s %s?>", token.Target, strings.TrimSpace(string(token.Inst))))
+
+		case xml.Directive:
+			lines = append(lines, indent()+commentColor.Sprint("<!"+string(token)+">"))
+		}
+	}
+
+	return lines
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlLines indents and colors HTML, one element per line.
+func htmlLines(body []byte) []string {
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+
+	var tokens []html.Token
+	for tokenizer.Next() != html.ErrorToken {
+		tokens = append(tokens, tokenizer.Token())
+	}
+	if !errors.Is(tokenizer.Err(), io.EOF) {
+		return textLines(body)
+	}
+
+	var lines []string
+	depth := 0
+	indent := func() string { return strings.Repeat("  ", depth) }
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		switch token.Type {
+		case html.StartTagToken:
+			open := tagColor.Sprint("<"+token.Data) + htmlAttributes(token.Attr) + tagColor.Sprint(">")
+			if voidElements[token.Data] {
+				lines = append(lines, indent()+open)
+				continue
+			}
+
+			if i+2 < len(tokens) && tokens[i+1].Type == html.TextToken && isInlineText(tokens[i+1].Data) &&
+				tokens[i+2].Type == html.EndTagToken && tokens[i+2].Data == token.Data {
+				lines = append(lines, indent()+open+textColor.Sprint(strings.TrimSpace(tokens[i+1].Data))+
+					tagColor.Sprint("</"+token.Data+">"))
+				i += 2
+				continue
+			}
+
+			lines = append(lines, indent()+open)
+			depth++
+
+		case html.SelfClosingTagToken:
+			lines = append(lines, indent()+tagColor.Sprint("<"+token.Data)+htmlAttributes(token.Attr)+tagColor.Sprint("/>"))
+
+		case html.EndTagToken:
+			depth = max(0, depth-1)
+			lines = append(lines, indent()+tagColor.Sprint("</"+token.Data+">"))
+
+		case html.TextToken:
+			lines = append(lines, textBlock(token.Data, indent())...)
+
+		case html.CommentToken:
+			lines = append(lines, indent()+commentColor.Sprint("<!--"+token.Data+"-->"))
+
+		case html.DoctypeToken:
+			lines = append(lines, indent()+commentColor.Sprint("<!DOCTYPE "+token.Data+">"))
+		}
+	}
+
+	return lines
+}
+
+func isInlineText(text string) bool {
+	text = strings.TrimSpace(text)
+	return text != "" && !strings.Contains(text, "\n")
+}
+
+func textBlock(text, indent string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, indent+textColor.Sprint(line))
+		}
+	}
+	return lines
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space != "" && !strings.Contains(name.Space, "/") {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+func xmlAttributes(attrs []xml.Attr) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		b.WriteString(" " + attrColor.Sprint(qualifiedName(attr.Name)) + "=" + valueColor.Sprintf("%q", attr.Value))
+	}
+	return b.String()
+}
+
+func htmlAttributes(attrs []html.Attribute) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		b.WriteString(" " + attrColor.Sprint(attr.Key) + "=" + valueColor.Sprintf("%q", attr.Val))
+	}
+	return b.String()
+}
+
+func yamlLines(body []byte) []string {
+	var lines []string
+	for _, line := range textLines(body) {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "#") || trimmed == "---" || trimmed == "...":
+			lines = append(lines, commentColor.Sprint(line))
+		case yamlKeyPattern.MatchString(line):
+			match := yamlKeyPattern.FindStringSubmatch(line)
+			lines = append(lines, match[1]+attrColor.Sprint(match[2])+":"+match[3]+yamlValue(match[4]))
+		case yamlListItem.MatchString(line):
+			match := yamlListItem.FindStringSubmatch(line)
+			lines = append(lines, match[1]+match[2]+yamlValue(match[3]))
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func yamlValue(value string) string {
+	text, comment, _ := strings.Cut(value, " #")
+	if comment != "" {
+		comment = commentColor.Sprint(" #" + comment)
+	}
+
+	switch {
+	case text == "" || text == "|" || text == ">":
+		return text + comment
+	case yamlLiteral.MatchString(strings.TrimSpace(text)):
+		return literalColor.Sprint(text) + comment
+	}
+	return valueColor.Sprint(text) + comment
+}
+
+// tableLines lays CSV/TSV out as a table, shrinking the widest columns
+// until the table fits the box.
+func tableLines(body []byte, separator rune) []string {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return textLines(body)
+	}
+
+	hidden := 0
+	if len(records) > maxTableRows+1 {
+		hidden = len(records) - maxTableRows - 1
+		records = records[:maxTableRows+1]
+	}
+
+	columns := 0
+	for _, record := range records {
+		columns = max(columns, len(record))
+	}
+
+	widths := make([]int, columns)
+	for _, record := range records {
+		for i, field := range record {
+			widths[i] = max(widths[i], utf8.RuneCountInString(field))
+		}
+	}
+
+	available := bodyWidth - 3*(columns-1)
+	for sum(widths) > available {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	var lines []string
+	for row, record := range records {
+		cells := make([]string, columns)
+		for i := range cells {
+			field := ""
+			if i < len(record) {
+				field = record[i]
+			}
+			cell := fitCell(field, widths[i])
+			if row == 0 {
+				cells[i] = headerColor.Sprint(cell)
+			} else {
+				cells[i] = textColor.Sprint(cell)
+			}
+		}
+		lines = append(lines, strings.Join(cells, commentColor.Sprint(" │ ")))
+
+		if row == 0 {
+			separators := make([]string, columns)
+			for i, width := range widths {
+				separators[i] = strings.Repeat("─", width)
+			}
+			lines = append(lines, commentColor.Sprint(strings.Join(separators, "─┼─")))
+		}
+	}
+
+	if hidden > 0 {
+		lines = append(lines, commentColor.Sprintf("… %d more rows", hidden))
+	}
+	return lines
+}
+
+func fitCell(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+// formLines decodes application/x-www-form-urlencoded pairs in their original order.
+func formLines(body []byte) []string {
+	var lines []string
+	for _, pair := range strings.Split(strings.TrimSpace(string(body)), "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			key = decoded
+		}
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			value = decoded
+		}
+		lines = append(lines, attrColor.Sprint(key)+" = "+textColor.Sprint(value))
+	}
+	return lines
+}
+
+var binaryMediaPrefixes = []string{"image/", "audio/", "video/", "font/"}
+
+var binaryMediaTypes = map[string]bool{
+	"application/octet-stream": true,
+	"application/pdf":          true,
+	"application/zip":          true,
+	"application/gzip":         true,
+	"application/x-protobuf":   true,
+	"application/protobuf":     true,
+	"application/grpc":         true,
+	"application/wasm":         true,
+	"application/msgpack":      true,
+	"application/x-msgpack":    true,
+	"application/cbor":         true,
+}
+
+func isBinary(mediaType string, body []byte) bool {
+	if mediaType == "image/svg+xml" {
+		return false
+	}
+	for _, prefix := range binaryMediaPrefixes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	if binaryMediaTypes[mediaType] {
+		return true
+	}
+
+	return !utf8.Valid(body) || bytes.IndexByte(body[:min(len(body), 512)], 0) != -1
+}
+
+// binaryLines shows the detected MIME type and a hex dump of the first bytes.
+func binaryLines(mediaType string, body []byte) []string {
+	detected := http.DetectContentType(body)
+	summary := fmt.Sprintf("Binary content, %s, detected as %s", FormatBytes(int64(len(body))), detected)
+	if mediaType != "" && !strings.HasPrefix(detected, mediaType) {
+		summary += fmt.Sprintf(" (declared %s)", mediaType)
+	}
+
+	return append([]string{literalColor.Sprint(summary), ""}, hexDumpLines(body, hexPreviewSize)...)
+}
+
+// hexDumpLines renders up to limit bytes as offset, hex and ASCII columns.
+func hexDumpLines(data []byte, limit int) []string {
+	var lines []string
+
+	preview := data[:min(len(data), limit)]
+	for offset := 0; offset < len(preview); offset += 16 {
+		chunk := preview[offset:min(offset+16, len(preview))]
+
+		hex := make([]string, 16)
+		var ascii strings.Builder
+		for i := range hex {
+			if i >= len(chunk) {
+				hex[i] = "  "
+				continue
+			}
+			hex[i] = fmt.Sprintf("%02x", chunk[i])
+			if chunk[i] >= 0x20 && chunk[i] < 0x7f {
+				ascii.WriteByte(chunk[i])
+			} else {
+				ascii.WriteByte('.')
+			}
+		}
+
+		lines = append(lines, commentColor.Sprintf("%04x", offset)+"  "+
+			textColor.Sprint(strings.Join(hex[:8], " "))+"  "+
+			textColor.Sprint(strings.Join(hex[8:], " "))+"  "+
+			valueColor.Sprintf("|%s|", ascii.String()))
+	}
+
+	if len(data) > len(preview) {
+		lines = append(lines, commentColor.Sprintf("… %s more", FormatBytes(int64(len(data)-len(preview)))))
+	}
+	return lines
+}
+
+func nonEmpty(lines []string) []string {
+	result := lines[:0]
+	for _, line := range lines {
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// truncateVisible cuts s to width visible characters, keeping ANSI color
+// codes intact and resetting them after the cut.
+func truncateVisible(s string, width int) string {
+	if visualLen(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "\x1b[") {
+			end := strings.IndexByte(s[i:], 'm')
+			if end != -1 {
+				b.WriteString(s[i : i+end+1])
+				i += end + 1
+				continue
+			}
+		}
+
+		if visible == width-1 {
+			break
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+
+	if strings.Contains(s, "\x1b[") {
+		return b.String() + "…\x1b[0m"
+	}
+	return b.String() + "…"
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+)
+
+type jsonFailure struct {
+	Error     string         `json:"error"`
+	Kind      string         `json:"kind"`
+	Phase     string         `json:"phase"`
+	Limit     string         `json:"limit,omitempty"`
+	Request   jsonFailed     `json:"request"`
+	Timing    jsonTiming     `json:"timing"`
+	Attempts  []jsonAttempt  `json:"attempts,omitempty"`
+	Redirects []jsonRedirect `json:"redirects,omitempty"`
+	TLS       *jsonTLS       `json:"tls,omitempty"`
+}
+
+type jsonFailed struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+func DisplayFailure(display structs.Display) error {
+	switch display.Output {
+	case structs.OutputJSON:
+		return displayFailureJSON(display)
+	case structs.OutputRaw:
+		return nil
+	}
+
+	failure := display.Failure
+	red := color.New(color.FgHiRed)
+	yellow := color.New(color.FgYellow)
+	white := color.New(color.FgHiWhite)
+	bold := color.New(color.Bold)
+
+	fmt.Println()
+	red.Println(failureTitle(failure.Kind))
+
+	red.Print("│ 🚀 ")
+	bold.Print(display.Method)
+	line := " " + truncateString(display.URL, 65)
+	fmt.Println(line + strings.Repeat(" ", max(0, boxWidth-4-len(display.Method)-visualLen(line))) + "│")
+
+	summary := failureSummary(failure, display.TotalTime)
+	yellow.Print("│ ")
+	white.Println(summary + strings.Repeat(" ", max(0, boxWidth-1-visualLen(summary))) + "│")
+
+	if display.Proxy != nil {
+		printListItem("Proxy", display.Proxy.Redacted())
+	}
+
+	if failure.Kind == structs.FailureError && failure.Err != nil {
+		printWrappedItem("Error", failure.Err.Error())
+	}
+
+	if failure.Hint != "" {
+		printWrappedItem("Hint", failure.Hint)
+	}
+
+	red.Println("╰" + strings.Repeat("─", boxWidth) + "╯")
+	fmt.Println()
+
+	DisplayAttempts(display.Attempts)
+	DisplayRedirects(display.Redirects)
+	if display.ShowTLS {
+		DisplayTLS(display.Timing.TLS)
+	}
+	DisplayTiming(display.Timing, display.TotalTime)
+	return nil
+}
+
+func failureTitle(kind string) string {
+	switch kind {
+	case structs.FailureTimeout:
+		return "╭─ ⏱️  TIMED OUT ─────────────────────────────────────────────────────────────╮"
+	case structs.FailureInterrupted:
+		return "╭─ 🛑 INTERRUPTED ────────────────────────────────────────────────────────────╮"
+	}
+	return "╭─ ❌ FAILED ─────────────────────────────────────────────────────────────────╮"
+}
+
+func failureSummary(failure *structs.Failure, elapsed time.Duration) string {
+	elapsed = elapsed.Round(time.Millisecond)
+
+	switch failure.Kind {
+	case structs.FailureTimeout:
+		if failure.Limit != "" {
+			return fmt.Sprintf("Timed out during %s after %s (%s)", failure.Phase, elapsed, failure.Limit)
+		}
+		return fmt.Sprintf("Timed out during %s after %s", failure.Phase, elapsed)
+	case structs.FailureInterrupted:
+		return fmt.Sprintf("Interrupted during %s after %s", failure.Phase, elapsed)
+	}
+	return fmt.Sprintf("Failed during %s after %s", failure.Phase, elapsed)
+}
+
+func displayFailureJSON(display structs.Display) error {
+	failure := display.Failure
+
+	message := failureSummary(failure, display.TotalTime)
+	if failure.Err != nil {
+		message = failure.Err.Error()
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(jsonFailure{
+		Error:     message,
+		Kind:      failure.Kind,
+		Phase:     failure.Phase,
+		Limit:     failure.Limit,
+		Request:   jsonFailed{Method: display.Method, URL: display.URL},
+		Timing:    buildJSONTiming(display.Timing, display.TotalTime),
+		Attempts:  buildJSONAttempts(display.Attempts),
+		Redirects: buildJSONRedirects(display.Redirects),
+		TLS:       tlsForJSON(display),
+	})
+}
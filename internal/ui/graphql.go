@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/graphql"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+)
+
+var (
+	builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+	typeKeywords = map[string]string{
+		"OBJECT":       "type",
+		"INTERFACE":    "interface",
+		"UNION":        "union",
+		"ENUM":         "enum",
+		"INPUT_OBJECT": "input",
+		"SCALAR":       "scalar",
+	}
+)
+
+// graphQLLines renders the data of a GraphQL response, or the type list of an
+// introspection query; errors get their own panel. It reports false for
+// bodies that are not GraphQL responses, which are shown as usual.
+func graphQLLines(display structs.Display) ([]string, bool) {
+	if display.GraphQL == nil {
+		return nil, false
+	}
+
+	resp, err := graphql.ParseResponse(display.Body)
+	if err != nil {
+		return nil, false
+	}
+
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return []string{commentColor.Sprint("data: null")}, true
+	}
+
+	if display.GraphQL.Introspection {
+		if schema, err := graphql.ParseSchema(resp.Data); err == nil {
+			return schemaLines(schema), true
+		}
+	}
+
+	return jsonLines(resp.Data), true
+}
+
+// DisplayGraphQLErrors lists the errors of a GraphQL response with their
+// path, locations and error code.
+func DisplayGraphQLErrors(display structs.Display) {
+	if display.GraphQL == nil {
+		return
+	}
+
+	resp, err := graphql.ParseResponse(display.Body)
+	if err != nil || len(resp.Errors) == 0 {
+		return
+	}
+
+	red := color.New(color.FgHiRed)
+	white := color.New(color.FgHiWhite)
+
+	red.Println("╭─ 🚨 GRAPHQL ERRORS ─────────────────────────────────────────────────────────╮")
+
+	for i, graphQLError := range resp.Errors {
+		if i > 0 {
+			white.Println("│" + strings.Repeat(" ", boxWidth) + "│")
+		}
+
+		for j, line := range wrapText(graphQLError.Message, boxWidth-4) {
+			prefix := "│ ✗ "
+			if j > 0 {
+				prefix = "│   "
+			}
+			red.Print(prefix)
+			color.New(color.FgHiRed, color.Bold).Print(line)
+			fmt.Println(strings.Repeat(" ", max(0, boxWidth-3-visualLen(line))) + "│")
+		}
+
+		if path := graphQLError.PathString(); path != "" {
+			printListItem("Path", path)
+		}
+		if locations := graphQLError.LocationsString(); locations != "" {
+			printListItem("Locations", locations)
+		}
+		if code, ok := graphQLError.Extensions["code"]; ok {
+			printListItem("Code", fmt.Sprint(code))
+		}
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+// schemaLines lists the schema types in SDL-like form, root types first.
+func schemaLines(schema *graphql.Schema) []string {
+	roots := schema.RootTypes()
+	rank := func(name string) int {
+		for i, root := range roots {
+			if root == name {
+				return i
+			}
+		}
+		return len(roots)
+	}
+
+	var types []graphql.Type
+	for _, t := range schema.Types {
+		if strings.HasPrefix(t.Name, "__") || builtinScalars[t.Name] {
+			continue
+		}
+		types = append(types, t)
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		if rank(types[i].Name) != rank(types[j].Name) {
+			return rank(types[i].Name) < rank(types[j].Name)
+		}
+		return types[i].Name < types[j].Name
+	})
+
+	lines := []string{commentColor.Sprintf("%d types", len(types))}
+	for _, t := range types {
+		lines = append(lines, "")
+		lines = append(lines, typeLines(t)...)
+	}
+	return lines
+}
+
+func typeLines(t graphql.Type) []string {
+	keyword := typeKeywords[t.Kind]
+	if keyword == "" {
+		keyword = strings.ToLower(t.Kind)
+	}
+	header := tagColor.Sprint(keyword) + " " + headerColor.Sprint(t.Name)
+
+	switch t.Kind {
+	case "UNION":
+		members := make([]string, len(t.PossibleTypes))
+		for i, member := range t.PossibleTypes {
+			members[i] = valueColor.Sprint(member.String())
+		}
+		return []string{header + " = " + strings.Join(members, " | ")}
+	case "OBJECT", "INTERFACE":
+		if len(t.Interfaces) > 0 {
+			interfaces := make([]string, len(t.Interfaces))
+			for i, iface := range t.Interfaces {
+				interfaces[i] = valueColor.Sprint(iface.String())
+			}
+			header += " " + tagColor.Sprint("implements") + " " + strings.Join(interfaces, " & ")
+		}
+	}
+
+	lines := []string{header}
+
+	for _, field := range t.Fields {
+		line := "  " + attrColor.Sprint(field.Name)
+		if len(field.Args) > 0 {
+			args := make([]string, len(field.Args))
+			for i, arg := range field.Args {
+				args[i] = arg.Name + ": " + valueColor.Sprint(arg.Type.String())
+			}
+			line += "(" + strings.Join(args, ", ") + ")"
+		}
+		line += ": " + valueColor.Sprint(field.Type.String())
+		if field.IsDeprecated {
+			line += commentColor.Sprint(" @deprecated")
+		}
+		lines = append(lines, line)
+	}
+
+	for _, field := range t.InputFields {
+		lines = append(lines, "  "+attrColor.Sprint(field.Name)+": "+valueColor.Sprint(field.Type.String()))
+	}
+
+	for _, value := range t.EnumValues {
+		line := "  " + literalColor.Sprint(value.Name)
+		if value.IsDeprecated {
+			line += commentColor.Sprint(" @deprecated")
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,400 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+	"github.com/tidwall/pretty"
+)
+
+type jsonGRPCCall struct {
+	Target     string           `json:"target"`
+	Method     string           `json:"method"`
+	Kind       string           `json:"kind"`
+	Transport  string           `json:"transport"`
+	Request    jsonGRPCRequest  `json:"request"`
+	Response   jsonGRPCResponse `json:"response"`
+	Timing     jsonTiming       `json:"timing"`
+	TLS        *jsonTLS         `json:"tls,omitempty"`
+	Assertions []jsonAssertion  `json:"assertions,omitempty"`
+}
+
+type jsonGRPCRequest struct {
+	Metadata map[string][]string `json:"metadata"`
+	Messages []json.RawMessage   `json:"messages"`
+}
+
+type jsonGRPCResponse struct {
+	Status   jsonGRPCStatus      `json:"status"`
+	Headers  map[string][]string `json:"headers"`
+	Messages []json.RawMessage   `json:"messages"`
+	Trailers map[string][]string `json:"trailers"`
+}
+
+type jsonGRPCStatus struct {
+	Code    int               `json:"code"`
+	Name    string            `json:"name"`
+	Message string            `json:"message,omitempty"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+type jsonGRPCMessage struct {
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	ElapsedMs float64         `json:"elapsed_ms"`
+}
+
+type jsonGRPCEnd struct {
+	Type     string              `json:"type"`
+	Status   jsonGRPCStatus      `json:"status"`
+	Headers  map[string][]string `json:"headers"`
+	Trailers map[string][]string `json:"trailers"`
+	Messages int                 `json:"messages"`
+	Timing   jsonTiming          `json:"timing"`
+}
+
+// DisplayGRPCStart shows the request before the call is made, so the
+// responses of a streaming call can follow it as they arrive.
+func DisplayGRPCStart(call structs.GRPCCall) {
+	if call.Output != structs.OutputPretty {
+		return
+	}
+
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+	yellow := color.New(color.FgYellow)
+
+	fmt.Println()
+	cyan.Println("╭─ 📤 REQUEST ────────────────────────────────────────────────────────────────╮")
+
+	printField(yellow, white, "Target:", call.Target+" "+grpcTransport(call))
+	printField(yellow, white, "Method:", call.Path)
+	printField(yellow, white, "Kind:", call.Method.Kind())
+	printField(yellow, white, "Input:", call.Method.Input)
+	printField(yellow, white, "Output:", call.Method.Output)
+
+	if len(call.Metadata) > 0 {
+		printField(yellow, white, "Metadata:", "")
+		for _, name := range sortedKeys(call.Metadata) {
+			for _, value := range call.Metadata[name] {
+				printListItem(name, MaskHeaderValue(name, value))
+			}
+		}
+	}
+
+	printField(yellow, white, grpcMessagesLabel(len(call.Requests)), "")
+	printGRPCMessages(call.Requests)
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+// DisplayGRPCMessage prints one response of a server streaming call as soon
+// as it arrives; json mode prints one NDJSON record per message.
+func DisplayGRPCMessage(call structs.GRPCCall, data []byte, elapsed time.Duration) {
+	switch call.Output {
+	case structs.OutputJSON:
+		record, _ := json.Marshal(jsonGRPCMessage{Type: "message", Data: data, ElapsedMs: milliseconds(elapsed)})
+		fmt.Println(string(record))
+	case structs.OutputRaw:
+		fmt.Println(string(data))
+	default:
+		color.New(color.FgHiMagenta, color.Bold).Printf("▸ #%d", len(call.Responses))
+		color.New(color.FgWhite).Printf("  +%.2fs\n", elapsed.Seconds())
+		fmt.Println("  " + string(pretty.Color(data, nil)))
+	}
+}
+
+// DisplayGRPC shows the outcome of a call: status, response metadata and
+// messages, timing and --fail. Live calls already printed their messages.
+func DisplayGRPC(call structs.GRPCCall) error {
+	switch call.Output {
+	case structs.OutputJSON:
+		return displayGRPCJSON(call)
+	case structs.OutputRaw:
+		if !call.Live {
+			for _, message := range call.Responses {
+				fmt.Println(string(message))
+			}
+		}
+		if call.Code != 0 {
+			color.New(color.FgHiRed).Fprintf(os.Stderr, "✗ %s: %s\n", call.CodeName, call.Message)
+		}
+		displayAssertions(os.Stderr, call.Assertions)
+		return nil
+	}
+
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+	yellow := color.New(color.FgYellow)
+	green := color.New(color.FgHiGreen)
+
+	if call.Live {
+		fmt.Println()
+	}
+	cyan.Println("╭─ 📥 RESPONSE ───────────────────────────────────────────────────────────────╮")
+
+	status := fmt.Sprintf("%s %d %s", grpcStatusEmoji(call.Code), call.Code, call.CodeName)
+	yellow.Printf("│ %-10s", "Status:")
+	color.New(grpcStatusColor(call.Code)).Print(status)
+	// The status emoji is one rune but two cells wide.
+	fmt.Println(strings.Repeat(" ", max(0, boxWidth-12-visualLen(status))) + "│")
+	if call.Message != "" {
+		printWrappedItem("Message", call.Message)
+	}
+	printField(yellow, green, "Time:", call.TotalTime.Round(time.Millisecond).String())
+
+	if len(call.Headers) > 0 {
+		printField(yellow, white, "Headers:", "")
+		printGRPCMetadata(call.Headers)
+	}
+
+	white.Println("│" + strings.Repeat(" ", boxWidth) + "│")
+	printField(yellow, white, grpcMessagesLabel(len(call.Responses)), "")
+	if call.Live && len(call.Responses) > 0 {
+		gray := color.New(color.FgWhite)
+		note := fmt.Sprintf("%d received, shown above", len(call.Responses))
+		gray.Println("│   " + note + strings.Repeat(" ", max(0, boxWidth-3-visualLen(note))) + "│")
+	} else {
+		printGRPCMessages(call.Responses)
+	}
+
+	if len(call.Trailers) > 0 {
+		white.Println("│" + strings.Repeat(" ", boxWidth) + "│")
+		printField(yellow, white, "Trailers:", "")
+		printGRPCMetadata(call.Trailers)
+	}
+
+	if len(call.Details) > 0 {
+		white.Println("│" + strings.Repeat(" ", boxWidth) + "│")
+		printField(yellow, white, "Details:", "")
+		for _, detail := range call.Details {
+			printBody(jsonLines(detail))
+		}
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+
+	if call.ShowTLS {
+		DisplayTLS(call.Timing.TLS)
+	}
+	DisplayTiming(call.Timing, call.TotalTime)
+	DisplayAssertions(call.Assertions)
+	return nil
+}
+
+// DisplayGRPCServices lists the services with the signature of each method.
+func DisplayGRPCServices(services []structs.GRPCService, output string) error {
+	switch output {
+	case structs.OutputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(buildJSONServices(services))
+	case structs.OutputRaw:
+		for _, service := range services {
+			for _, method := range service.Methods {
+				fmt.Println(service.Name + "/" + method.Name)
+			}
+		}
+		return nil
+	}
+
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+	gray := color.New(color.FgWhite)
+
+	fmt.Println()
+	cyan.Println("╭─ 🧩 SERVICES ───────────────────────────────────────────────────────────────╮")
+	if len(services) == 0 {
+		gray.Println("│   (no services)" + strings.Repeat(" ", boxWidth-16) + "│")
+	}
+
+	for i, service := range services {
+		if i > 0 {
+			white.Println("│" + strings.Repeat(" ", boxWidth) + "│")
+		}
+		name := truncateString(service.Name, boxWidth-2)
+		cyan.Println("│ " + name + strings.Repeat(" ", max(0, boxWidth-1-visualLen(name))) + "│")
+
+		for _, method := range service.Methods {
+			signature := fmt.Sprintf("(%s%s) returns (%s%s)",
+				streamPrefix(method.ClientStreaming), localTypeName(service.Name, method.Input),
+				streamPrefix(method.ServerStreaming), localTypeName(service.Name, method.Output))
+			prefix := "│   rpc " + method.Name
+			signature = truncateString(signature, max(4, boxWidth-visualLen(prefix)-1))
+			white.Print(prefix + " ")
+			gray.Println(signature + strings.Repeat(" ", max(0, boxWidth-visualLen(prefix)-visualLen(signature))) + "│")
+		}
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+	return nil
+}
+
+func buildJSONServices(services []structs.GRPCService) []map[string]any {
+	result := make([]map[string]any, 0, len(services))
+	for _, service := range services {
+		methods := make([]map[string]any, 0, len(service.Methods))
+		for _, method := range service.Methods {
+			methods = append(methods, map[string]any{
+				"name":             method.Name,
+				"input":            method.Input,
+				"output":           method.Output,
+				"client_streaming": method.ClientStreaming,
+				"server_streaming": method.ServerStreaming,
+			})
+		}
+		result = append(result, map[string]any{"name": service.Name, "methods": methods})
+	}
+	return result
+}
+
+// displayGRPCJSON prints one document, or the closing record after the
+// NDJSON messages of a live call.
+func displayGRPCJSON(call structs.GRPCCall) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+
+	status := jsonGRPCStatus{
+		Code:    call.Code,
+		Name:    call.CodeName,
+		Message: call.Message,
+		Details: rawMessages(call.Details),
+	}
+
+	if call.Live {
+		return encoder.Encode(jsonGRPCEnd{
+			Type:     "status",
+			Status:   status,
+			Headers:  call.Headers,
+			Trailers: call.Trailers,
+			Messages: len(call.Responses),
+			Timing:   buildJSONTiming(call.Timing, call.TotalTime),
+		})
+	}
+
+	var tls *jsonTLS
+	if call.ShowTLS {
+		tls = buildJSONTLS(call.Timing.TLS)
+	}
+
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonGRPCCall{
+		Target:    call.Target,
+		Method:    call.Path,
+		Kind:      call.Method.Kind(),
+		Transport: strings.Trim(grpcTransport(call), "()"),
+		Request: jsonGRPCRequest{
+			Metadata: maskedMetadata(call.Metadata),
+			Messages: rawMessages(call.Requests),
+		},
+		Response: jsonGRPCResponse{
+			Status:   status,
+			Headers:  call.Headers,
+			Messages: rawMessages(call.Responses),
+			Trailers: call.Trailers,
+		},
+		Timing:     buildJSONTiming(call.Timing, call.TotalTime),
+		TLS:        tls,
+		Assertions: buildJSONAssertions(call.Assertions),
+	})
+}
+
+func rawMessages(messages [][]byte) []json.RawMessage {
+	raw := make([]json.RawMessage, len(messages))
+	for i, message := range messages {
+		raw[i] = message
+	}
+	return raw
+}
+
+func maskedMetadata(md map[string][]string) map[string][]string {
+	masked := make(map[string][]string, len(md))
+	for name, values := range md {
+		for _, value := range values {
+			masked[name] = append(masked[name], MaskHeaderValue(name, value))
+		}
+	}
+	return masked
+}
+
+// printGRPCMessages shows a single message pretty-printed and several as one
+// compact line each, like NDJSON.
+func printGRPCMessages(messages [][]byte) {
+	switch len(messages) {
+	case 0:
+		gray := color.New(color.FgWhite)
+		gray.Println("│   (no messages)" + strings.Repeat(" ", boxWidth-16) + "│")
+	case 1:
+		printBody(jsonLines(messages[0]))
+	default:
+		for _, message := range messages {
+			printBody([]string{string(pretty.Color(message, nil))})
+		}
+	}
+}
+
+func printGRPCMetadata(md map[string][]string) {
+	for _, name := range sortedKeys(md) {
+		for _, value := range md[name] {
+			printListItem(name, MaskHeaderValue(name, value))
+		}
+	}
+}
+
+func grpcMessagesLabel(count int) string {
+	if count == 1 {
+		return "Message:"
+	}
+	return "Messages:"
+}
+
+func grpcTransport(call structs.GRPCCall) string {
+	switch {
+	case call.Web:
+		return "(grpc-web)"
+	case call.Secure:
+		return "(tls)"
+	}
+	return "(plaintext)"
+}
+
+// localTypeName drops the package of a message declared next to the
+// service, as it would be written in the .proto file.
+func localTypeName(service, typeName string) string {
+	if dot := strings.LastIndex(service, "."); dot >= 0 {
+		if name, ok := strings.CutPrefix(typeName, service[:dot+1]); ok && !strings.Contains(name, ".") {
+			return name
+		}
+	}
+	return typeName
+}
+
+func streamPrefix(streaming bool) string {
+	if streaming {
+		return "stream "
+	}
+	return ""
+}
+
+func grpcStatusEmoji(code int) string {
+	if code == 0 {
+		return "✨"
+	}
+	return "❌"
+}
+
+func grpcStatusColor(code int) color.Attribute {
+	if code == 0 {
+		return color.FgHiGreen
+	}
+	return color.FgHiRed
+}
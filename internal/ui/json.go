@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+type jsonOutput struct {
+	Request    jsonRequest     `json:"request"`
+	Response   jsonResponse    `json:"response"`
+	Timing     jsonTiming      `json:"timing"`
+	Attempts   []jsonAttempt   `json:"attempts,omitempty"`
+	Redirects  []jsonRedirect  `json:"redirects,omitempty"`
+	TLS        *jsonTLS        `json:"tls,omitempty"`
+	Assertions []jsonAssertion `json:"assertions,omitempty"`
+}
+
+type jsonRequest struct {
+	Method   string              `json:"method"`
+	URL      string              `json:"url"`
+	Headers  map[string][]string `json:"headers"`
+	BodySize int64               `json:"body_size"`
+	Proxy    string              `json:"proxy,omitempty"`
+}
+
+type jsonResponse struct {
+	Status     int                 `json:"status"`
+	StatusText string              `json:"status_text"`
+	Proto      string              `json:"proto"`
+	Headers    map[string][]string `json:"headers"`
+	Size       int64               `json:"size"`
+	Filter     string              `json:"filter,omitempty"`
+	SavedTo    string              `json:"saved_to,omitempty"`
+	Body       any                 `json:"body"`
+}
+
+type jsonTiming struct {
+	DNSLookupMs    *float64 `json:"dns_lookup_ms,omitempty"`
+	TCPConnectMs   *float64 `json:"tcp_connect_ms,omitempty"`
+	ProxyConnectMs *float64 `json:"proxy_connect_ms,omitempty"`
+	TLSHandshakeMs *float64 `json:"tls_handshake_ms,omitempty"`
+	FirstByteMs    *float64 `json:"first_byte_ms,omitempty"`
+	TransferMs     *float64 `json:"transfer_ms,omitempty"`
+	TotalMs        float64  `json:"total_ms"`
+}
+
+func DisplayJSON(display structs.Display) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(buildJSONOutput(display))
+}
+
+func DisplayRaw(display structs.Display) error {
+	body := display.Body
+	if display.Filter != "" {
+		body = rawFilterResults(display.FilterResults)
+	}
+
+	_, err := os.Stdout.Write(body)
+	displayAssertions(os.Stderr, display.Assertions)
+	return err
+}
+
+func buildJSONOutput(display structs.Display) jsonOutput {
+	resp := display.Response
+
+	return jsonOutput{
+		Request: jsonRequest{
+			Method:   display.Method,
+			URL:      display.URL,
+			Headers:  maskedHeaders(display.Request.Header),
+			BodySize: requestBodySize(display),
+			Proxy:    redactedProxy(display),
+		},
+		Response: jsonResponse{
+			Status:     resp.StatusCode,
+			StatusText: http.StatusText(resp.StatusCode),
+			Proto:      resp.Proto,
+			Headers:    maskedHeaders(resp.Header),
+			Size:       responseSize(display),
+			Filter:     display.Filter,
+			SavedTo:    savedPath(display),
+			Body:       responseBody(display),
+		},
+		Timing:     buildJSONTiming(display.Timing, display.TotalTime),
+		Attempts:   buildJSONAttempts(display.Attempts),
+		Redirects:  buildJSONRedirects(display.Redirects),
+		TLS:        tlsForJSON(display),
+		Assertions: buildJSONAssertions(display.Assertions),
+	}
+}
+
+func savedPath(display structs.Display) string {
+	if display.Saved == nil {
+		return ""
+	}
+	return display.Saved.Path
+}
+
+func maskedHeaders(header http.Header) map[string][]string {
+	masked := make(map[string][]string, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			masked[name] = append(masked[name], MaskHeaderValue(name, value))
+		}
+	}
+	return masked
+}
+
+func requestBodySize(display structs.Display) int64 {
+	if display.DataFile != "" || len(display.Form) > 0 {
+		return display.DataSize
+	}
+	return int64(len(display.Data))
+}
+
+func tlsForJSON(display structs.Display) *jsonTLS {
+	if !display.ShowTLS || display.Timing == nil {
+		return nil
+	}
+	return buildJSONTLS(display.Timing.TLS)
+}
+
+func redactedProxy(display structs.Display) string {
+	if display.Proxy == nil {
+		return ""
+	}
+	return display.Proxy.Redacted()
+}
+
+// responseBody is the filtered value for a single --filter result, an array
+// of them for several, or the decoded body when there is no filter.
+func responseBody(display structs.Display) any {
+	if display.Filter == "" {
+		return jsonBody(display.Body)
+	}
+	if len(display.FilterResults) == 1 {
+		return display.FilterResults[0]
+	}
+	if display.FilterResults == nil {
+		return []any{}
+	}
+	return display.FilterResults
+}
+
+// rawFilterResults prints one result per line like jq -r: strings unquoted,
+// everything else as compact JSON.
+func rawFilterResults(results []any) []byte {
+	var buf bytes.Buffer
+	for _, result := range results {
+		if text, ok := result.(string); ok {
+			buf.WriteString(text)
+		} else {
+			buf.Write(encodeFilterResult(result))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func encodeFilterResult(result any) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(result); err != nil {
+		return []byte(fmt.Sprint(result))
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+// jsonBody embeds JSON responses as-is and falls back to a string for anything else.
+func jsonBody(body []byte) any {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	return string(body)
+}
+
+func buildJSONTiming(timing *structs.TimingInfo, totalTime time.Duration) jsonTiming {
+	result := jsonTiming{TotalMs: milliseconds(totalTime)}
+
+	result.DNSLookupMs = phaseMs(timing.DNSStart, timing.DNSDone)
+	result.TCPConnectMs = phaseMs(timing.ConnectStart, timing.ConnectDone)
+	result.ProxyConnectMs = phaseMs(timing.ProxyConnectStart, timing.ProxyConnectDone)
+	result.TLSHandshakeMs = phaseMs(timing.TLSStart, timing.TLSDone)
+	result.FirstByteMs = phaseMs(timing.RequestStart, timing.ResponseStart)
+	result.TransferMs = phaseMs(timing.ResponseStart, timing.ResponseDone)
+
+	return result
+}
+
+func phaseMs(start, end time.Time) *float64 {
+	if start.IsZero() || end.IsZero() {
+		return nil
+	}
+	ms := milliseconds(end.Sub(start))
+	return &ms
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	progressBarWidth = 24
+	progressInterval = 100 * time.Millisecond
+)
+
+// Progress draws a download progress bar on stderr. It is an io.Writer so it
+// can count bytes through an io.TeeReader; nothing is drawn when stderr is
+// not a terminal.
+type Progress struct {
+	name     string
+	offset   int64
+	total    int64
+	current  int64
+	start    time.Time
+	lastDraw time.Time
+	enabled  bool
+}
+
+// NewProgress starts a bar for name; offset is the size already on disk when
+// resuming and total is -1 when the length is unknown.
+func NewProgress(name string, offset, total int64) *Progress {
+	return &Progress{
+		name:    name,
+		offset:  offset,
+		total:   total,
+		current: offset,
+		start:   time.Now(),
+		enabled: isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd()),
+	}
+}
+
+func (p *Progress) Write(b []byte) (int, error) {
+	p.current += int64(len(b))
+	if p.enabled && time.Since(p.lastDraw) >= progressInterval {
+		p.draw()
+		p.lastDraw = time.Now()
+	}
+	return len(b), nil
+}
+
+// Finish draws the final state and moves to a new line.
+func (p *Progress) Finish() {
+	if !p.enabled {
+		return
+	}
+	p.draw()
+	fmt.Fprintln(os.Stderr)
+}
+
+func (p *Progress) draw() {
+	cyan := color.New(color.FgHiCyan)
+	gray := color.New(color.FgWhite)
+
+	elapsed := time.Since(p.start)
+	rate := float64(0)
+	if elapsed > 0 {
+		rate = float64(p.current-p.offset) / elapsed.Seconds()
+	}
+
+	line := "⬇ " + truncateString(p.name, 24) + "  " + FormatBytes(p.current)
+	if p.total > 0 {
+		ratio := min(1, float64(p.current)/float64(p.total))
+		filled := int(ratio * progressBarWidth)
+		line += " / " + FormatBytes(p.total) +
+			"  [" + strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled) + "]" +
+			fmt.Sprintf(" %3.0f%%", ratio*100)
+	}
+
+	stats := fmt.Sprintf("  %s/s", FormatBytes(int64(rate)))
+	if p.total > 0 && rate > 0 && p.current < p.total {
+		eta := time.Duration(float64(p.total-p.current) / rate * float64(time.Second))
+		stats += "  ETA " + eta.Round(time.Second).String()
+	}
+
+	fmt.Fprint(os.Stderr, "\r\x1b[K")
+	cyan.Fprint(os.Stderr, line)
+	gray.Fprint(os.Stderr, stats)
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+)
+
+type jsonRedirect struct {
+	Method     string  `json:"method"`
+	URL        string  `json:"url"`
+	Status     int     `json:"status"`
+	Location   string  `json:"location"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+func DisplayRedirects(redirects []structs.Redirect) {
+	if len(redirects) == 0 {
+		return
+	}
+
+	yellow := color.New(color.FgHiYellow)
+	white := color.New(color.FgHiWhite)
+	gray := color.New(color.FgWhite)
+
+	yellow.Println("╭─ ↪️  REDIRECTS ──────────────────────────────────────────────────────────────╮")
+
+	for i, redirect := range redirects {
+		prefix := fmt.Sprintf("│ %d. ", i+1)
+		status := fmt.Sprintf("%d %s ", redirect.StatusCode, redirect.Method)
+		duration := redirect.Duration.Round(time.Millisecond).String()
+		from := truncateString(redirect.URL, max(4, boxWidth-visualLen(prefix)-len(status)-len(duration)-2))
+
+		gray.Print(prefix)
+		color.New(GetColorByStatus(redirect.StatusCode)).Print(status)
+		white.Print(from)
+		gray.Print(strings.Repeat(" ", max(1, boxWidth-visualLen(prefix)-len(status)-visualLen(from)-len(duration))) + duration)
+		fmt.Println(" │")
+
+		arrow := "│    → "
+		location := truncateString(redirect.Location, boxWidth+1-visualLen(arrow))
+		gray.Print(arrow)
+		white.Println(location + strings.Repeat(" ", max(0, boxWidth+1-visualLen(arrow)-visualLen(location))) + "│")
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+func buildJSONRedirects(redirects []structs.Redirect) []jsonRedirect {
+	result := make([]jsonRedirect, 0, len(redirects))
+	for _, redirect := range redirects {
+		result = append(result, jsonRedirect{
+			Method:     redirect.Method,
+			URL:        redirect.URL,
+			Status:     redirect.StatusCode,
+			Location:   redirect.Location,
+			DurationMs: milliseconds(redirect.Duration),
+		})
+	}
+	return result
+}
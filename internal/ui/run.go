@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+)
+
+// DisplayRunStep announces each request of a .http file on stderr, so the
+// json/raw output of the requests themselves stays clean on stdout.
+func DisplayRunStep(number, total int, label, location string) {
+	cyan := color.New(color.FgHiCyan)
+	bold := color.New(color.Bold)
+	gray := color.New(color.FgWhite)
+
+	cyan.Fprintf(os.Stderr, "▶ [%d/%d] ", number, total)
+	bold.Fprint(os.Stderr, label)
+	gray.Fprintf(os.Stderr, "  %s\n", location)
+}
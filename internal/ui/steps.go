@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+)
+
+// DisplaySteps prints the collection summary table. With json/raw output it
+// goes to stderr so stdout keeps only the per-request documents.
+func DisplaySteps(name string, steps []structs.StepResult, totalTime time.Duration, output string) {
+	var w io.Writer = os.Stdout
+	if output != structs.OutputPretty {
+		w = os.Stderr
+	}
+
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+	gray := color.New(color.FgWhite)
+	bold := color.New(color.Bold)
+	red := color.New(color.FgHiRed)
+
+	cyan.Fprintln(w, "╭─ 📋 SUMMARY ────────────────────────────────────────────────────────────────╮")
+
+	if name != "" {
+		title := truncateString(name, boxWidth-2)
+		gray.Fprint(w, "│ ")
+		bold.Fprint(w, title)
+		fmt.Fprintln(w, strings.Repeat(" ", max(0, boxWidth-1-visualLen(title)))+"│")
+	}
+
+	header := fmt.Sprintf(" %-3s %-20s %-7s %-8s %-8s %s", "#", "STEP", "METHOD", "STATUS", "TIME", "CAPTURED")
+	gray.Fprint(w, "│")
+	gray.Fprint(w, header)
+	fmt.Fprintln(w, strings.Repeat(" ", max(0, boxWidth-len(header)))+"│")
+
+	completed := 0
+	for i, step := range steps {
+		status, statusColor := stepStatus(step)
+		if step.Error == "" && !step.Skipped {
+			completed++
+		}
+
+		duration := ""
+		if !step.Skipped {
+			duration = step.Duration.Round(time.Millisecond).String()
+		}
+
+		captured := truncateString(strings.Join(step.Captured, ", "), boxWidth-52)
+		row := fmt.Sprintf(" %-3d %-20s %-7s ", i+1, truncateString(step.Name, 20), truncateString(step.Method, 7))
+
+		gray.Fprint(w, "│")
+		white.Fprint(w, row)
+		color.New(statusColor).Fprintf(w, "%-8s ", status)
+		gray.Fprintf(w, "%-8s ", duration)
+		white.Fprint(w, captured)
+		fmt.Fprintln(w, strings.Repeat(" ", max(0, boxWidth-visualLen(row)-18-visualLen(captured)))+"│")
+
+		if step.Error != "" {
+			for _, line := range wrapText("✗ "+step.Error, boxWidth-6) {
+				gray.Fprint(w, "│     ")
+				red.Fprint(w, line)
+				fmt.Fprintln(w, strings.Repeat(" ", max(0, boxWidth-5-visualLen(line)))+"│")
+			}
+		}
+	}
+
+	white.Fprintln(w, "├─────────────────────────────────────────────────────────────────────────────┤")
+
+	footer := fmt.Sprintf(" %d/%d steps completed  │  ⏱️  %s", completed, len(steps), totalTime.Round(time.Millisecond))
+	footerColor := color.FgHiGreen
+	if completed < len(steps) {
+		footerColor = color.FgHiRed
+	}
+	gray.Fprint(w, "│")
+	color.New(footerColor).Fprint(w, footer)
+	// ⏱️ is two runes but renders as two cells, so visualLen already matches.
+	fmt.Fprintln(w, strings.Repeat(" ", max(0, boxWidth-visualLen(footer)))+"│")
+
+	white.Fprintln(w, "╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Fprintln(w)
+}
+
+func stepStatus(step structs.StepResult) (string, color.Attribute) {
+	switch {
+	case step.Skipped:
+		return "skipped", color.FgWhite
+	case step.StatusCode == 0:
+		return "error", color.FgHiRed
+	case step.Error != "":
+		return fmt.Sprintf("%d ✗", step.StatusCode), color.FgHiRed
+	}
+	return fmt.Sprintf("%d ✓", step.StatusCode), GetColorByStatus(step.StatusCode)
+}
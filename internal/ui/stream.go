@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+	"github.com/tidwall/pretty"
+)
+
+type jsonStreamEvent struct {
+	Type      string  `json:"type"`
+	Event     string  `json:"event,omitempty"`
+	ID        string  `json:"id,omitempty"`
+	Data      any     `json:"data"`
+	ElapsedMs float64 `json:"elapsed_ms"`
+}
+
+// DisplayStreamStart opens a streamed response in pretty mode; the records
+// follow as they arrive instead of inside the response box.
+func DisplayStreamStart(method, url string, resp *http.Response, mode, output string) {
+	if output != structs.OutputPretty {
+		return
+	}
+
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+	gray := color.New(color.FgWhite)
+	bold := color.New(color.Bold)
+
+	statusColor := GetColorByStatus(resp.StatusCode)
+	status := fmt.Sprintf("%s %d %s", GetEmojiByStatusCode(resp.StatusCode), resp.StatusCode, http.StatusText(resp.StatusCode))
+	details := fmt.Sprintf("  │  %s  │  %s", mode, resp.Header.Get("Content-Type"))
+	location := truncateString(url, 65-len(method))
+
+	fmt.Println()
+	cyan.Println("╭─ 📡 STREAM ─────────────────────────────────────────────────────────────────╮")
+
+	cyan.Print("│ 🚀 ")
+	bold.Print(method)
+	fmt.Println(" " + location + strings.Repeat(" ", max(0, boxWidth-5-len(method)-visualLen(location))) + "│")
+
+	cyan.Print("│ ")
+	color.New(statusColor, color.Bold).Print(status)
+	details = truncateString(details, boxWidth-2-visualLen(status))
+	gray.Print(details)
+	fmt.Println(strings.Repeat(" ", max(0, boxWidth-2-visualLen(status)-visualLen(details))) + "│")
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+// DisplayStreamEvent prints one record as soon as it is parsed. In json mode
+// every record becomes one compact line, so the output is itself NDJSON.
+func DisplayStreamEvent(event structs.StreamEvent, output string) {
+	if output == structs.OutputJSON {
+		data, _ := json.Marshal(jsonStreamEvent{
+			Type:      event.Mode,
+			Event:     event.Event,
+			ID:        event.ID,
+			Data:      jsonBody([]byte(event.Data)),
+			ElapsedMs: milliseconds(event.Elapsed),
+		})
+		fmt.Println(string(data))
+		return
+	}
+
+	magenta := color.New(color.FgHiMagenta, color.Bold)
+	gray := color.New(color.FgWhite)
+	elapsed := fmt.Sprintf("+%.2fs", event.Elapsed.Seconds())
+
+	switch event.Mode {
+	case structs.StreamSSE:
+		magenta.Print("▸ " + event.Event)
+		if event.ID != "" {
+			gray.Print("  id=" + event.ID)
+		}
+		gray.Println("  " + elapsed)
+		for _, line := range sseDataLines(event.Data) {
+			fmt.Println("  " + line)
+		}
+	case structs.StreamNDJSON:
+		gray.Println("▸ " + elapsed)
+		for _, line := range jsonLines([]byte(event.Data)) {
+			fmt.Println("  " + line)
+		}
+	default:
+		gray.Print(fmt.Sprintf("%8s │ ", elapsed))
+		fmt.Println(event.Data)
+	}
+}
+
+// sseDataLines keeps JSON payloads on a single colored line, since SSE streams
+// often carry one small object per token.
+func sseDataLines(data string) []string {
+	if json.Valid([]byte(data)) {
+		return []string{string(pretty.Color(pretty.Ugly([]byte(data)), nil))}
+	}
+	return SplitLines(data)
+}
+
+// DisplayStreamReconnect reports a dropped SSE connection on stderr while
+// charm waits to reopen it.
+func DisplayStreamReconnect(lastID string, delay time.Duration, err error) {
+	yellow := color.New(color.FgYellow)
+	gray := color.New(color.FgWhite)
+
+	reason := "stream closed"
+	if err != nil {
+		reason = err.Error()
+	}
+
+	yellow.Fprintf(os.Stderr, "↻ %s, reconnecting in %s", reason, delay.Round(time.Millisecond))
+	if lastID != "" {
+		gray.Fprintf(os.Stderr, " (Last-Event-ID: %s)", lastID)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// DisplayStreamEnd closes the record list with the stream totals.
+func DisplayStreamEnd(summary *structs.StreamSummary) {
+	gray := color.New(color.FgWhite)
+
+	parts := []string{
+		fmt.Sprintf("%d events", summary.Events),
+		FormatBytes(summary.Bytes),
+	}
+	if summary.Reconnects > 0 {
+		parts = append(parts, fmt.Sprintf("%d reconnect(s)", summary.Reconnects))
+	}
+	if summary.StopReason != "" {
+		parts = append(parts, summary.StopReason)
+	}
+
+	fmt.Println()
+	gray.Println("⏹ Stream ended · " + strings.Join(parts, " · "))
+	fmt.Println()
+}
+
+// displayStreamed finishes a response whose records were already printed:
+// pretty mode adds the totals, timing and assertions, json and raw add nothing
+// to stdout so the streamed output stays parseable.
+func displayStreamed(display structs.Display) error {
+	if display.Output != structs.OutputPretty {
+		displayAssertions(os.Stderr, display.Assertions)
+		return nil
+	}
+
+	DisplayStreamEnd(display.Stream)
+	DisplayAttempts(display.Attempts)
+	DisplayRedirects(display.Redirects)
+	if display.ShowTLS {
+		DisplayTLS(display.Timing.TLS)
+	}
+	DisplayTiming(display.Timing, display.TotalTime)
+	DisplayAssertions(display.Assertions)
+	return nil
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/crypto/ocsp"
+)
+
+type jsonTLS struct {
+	// HandshakeFailed leaves only the chain: nothing else was negotiated.
+	HandshakeFailed bool              `json:"handshake_failed,omitempty"`
+	Version         string            `json:"version,omitempty"`
+	CipherSuite     string            `json:"cipher_suite,omitempty"`
+	ALPN            string            `json:"alpn,omitempty"`
+	ServerName      string            `json:"server_name,omitempty"`
+	Resumed         bool              `json:"resumed"`
+	OCSP            string            `json:"ocsp,omitempty"`
+	Chain           []jsonCertificate `json:"chain"`
+}
+
+type jsonCertificate struct {
+	Subject           string    `json:"subject"`
+	Issuer            string    `json:"issuer"`
+	SANs              []string  `json:"sans,omitempty"`
+	NotBefore         time.Time `json:"not_before"`
+	NotAfter          time.Time `json:"not_after"`
+	DaysUntilExpiry   int       `json:"days_until_expiry"`
+	SHA256Fingerprint string    `json:"sha256_fingerprint"`
+}
+
+func DisplayTLS(state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+
+	green := color.New(color.FgHiGreen)
+	white := color.New(color.FgHiWhite)
+	yellow := color.New(color.FgYellow)
+	cyan := color.New(color.FgHiCyan)
+
+	green.Println("╭─ 🔒 TLS ────────────────────────────────────────────────────────────────────╮")
+
+	if state.HandshakeComplete {
+		printField(yellow, white, "Version:", tls.VersionName(state.Version))
+		printField(yellow, white, "Cipher:", tls.CipherSuiteName(state.CipherSuite))
+		printField(yellow, white, "ALPN:", valueOr(state.NegotiatedProtocol, "(none)"))
+		printField(yellow, white, "SNI:", valueOr(state.ServerName, "(none)"))
+		printField(yellow, white, "Resumed:", yesNo(state.DidResume))
+		printField(yellow, white, "OCSP:", ocspStatus(state))
+	} else {
+		printField(yellow, color.New(color.FgHiRed), "Status:", "handshake failed, chain as sent by the server")
+	}
+
+	for i, cert := range state.PeerCertificates {
+		white.Println("│" + strings.Repeat(" ", boxWidth) + "│")
+
+		title := fmt.Sprintf("│ Certificate #%d%s", i, certificateRole(i, cert))
+		cyan.Println(title + strings.Repeat(" ", max(0, boxWidth+1-visualLen(title))) + "│")
+
+		printWrappedItem("Subject", cert.Subject.String())
+		printWrappedItem("Issuer", cert.Issuer.String())
+		if sans := certificateSANs(cert); len(sans) > 0 {
+			printWrappedItem("SANs", strings.Join(sans, ", "))
+		}
+		printValidity(cert)
+		// Split in two groups of 16 bytes so the fingerprint wraps cleanly.
+		sha := fingerprint(cert)
+		printWrappedItem("SHA-256", sha[:47]+" "+sha[48:])
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+func printField(label, value *color.Color, name, text string) {
+	label.Printf("│ %-10s", name)
+	text = truncateString(text, boxWidth-11)
+	value.Println(text + strings.Repeat(" ", max(0, boxWidth-11-visualLen(text))) + "│")
+}
+
+func printValidity(cert *x509.Certificate) {
+	gray := color.New(color.FgWhite)
+
+	days := daysUntil(cert.NotAfter)
+	remaining := fmt.Sprintf("%d days left", days)
+	remainingColor := color.New(color.FgHiGreen)
+	switch {
+	case time.Now().Before(cert.NotBefore):
+		remaining = "not yet valid"
+		remainingColor = color.New(color.FgHiRed)
+	case days < 0:
+		remaining = fmt.Sprintf("expired %d days ago", -days)
+		remainingColor = color.New(color.FgHiRed)
+	case days < 30:
+		remainingColor = color.New(color.FgHiYellow)
+	}
+
+	prefix := "│   • Valid: "
+	period := fmt.Sprintf("%s → %s ", cert.NotBefore.Format(time.DateOnly), cert.NotAfter.Format(time.DateOnly))
+	gray.Print(prefix)
+	color.New(color.FgHiWhite).Print(period)
+	remainingColor.Print("(" + remaining + ")")
+	fmt.Println(strings.Repeat(" ", max(0, boxWidth+1-visualLen(prefix)-visualLen(period)-len(remaining)-2)) + "│")
+}
+
+func certificateRole(index int, cert *x509.Certificate) string {
+	switch {
+	case index == 0:
+		return " (leaf)"
+	case cert.Subject.String() == cert.Issuer.String():
+		return " (root)"
+	}
+	return " (intermediate)"
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// daysUntil rounds down, so a certificate that expired within the last day
+// counts as expired rather than as 0 days left.
+func daysUntil(t time.Time) int {
+	return int(math.Floor(time.Until(t).Hours() / 24))
+}
+
+func ocspStatus(state *tls.ConnectionState) string {
+	if len(state.OCSPResponse) == 0 {
+		return "not stapled"
+	}
+
+	var issuer *x509.Certificate
+	if len(state.PeerCertificates) > 1 {
+		issuer = state.PeerCertificates[1]
+	}
+
+	response, err := ocsp.ParseResponse(state.OCSPResponse, issuer)
+	if err != nil {
+		return "stapled (invalid response: " + err.Error() + ")"
+	}
+
+	status := "unknown"
+	switch response.Status {
+	case ocsp.Good:
+		status = "good"
+	case ocsp.Revoked:
+		status = "REVOKED on " + response.RevokedAt.Format(time.DateOnly)
+	}
+
+	if response.NextUpdate.IsZero() {
+		return "stapled, " + status
+	}
+	return fmt.Sprintf("stapled, %s (next update %s)", status, response.NextUpdate.Format(time.DateOnly))
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func buildJSONTLS(state *tls.ConnectionState) *jsonTLS {
+	if state == nil {
+		return nil
+	}
+
+	result := &jsonTLS{HandshakeFailed: !state.HandshakeComplete, Chain: []jsonCertificate{}}
+	if state.HandshakeComplete {
+		result.Version = tls.VersionName(state.Version)
+		result.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+		result.ALPN = state.NegotiatedProtocol
+		result.ServerName = state.ServerName
+		result.Resumed = state.DidResume
+		result.OCSP = ocspStatus(state)
+	}
+
+	for _, cert := range state.PeerCertificates {
+		result.Chain = append(result.Chain, jsonCertificate{
+			Subject:           cert.Subject.String(),
+			Issuer:            cert.Issuer.String(),
+			SANs:              certificateSANs(cert),
+			NotBefore:         cert.NotBefore,
+			NotAfter:          cert.NotAfter,
+			DaysUntilExpiry:   daysUntil(cert.NotAfter),
+			SHA256Fingerprint: fingerprint(cert),
+		})
+	}
+	return result
+}
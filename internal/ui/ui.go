@@ -3,23 +3,54 @@ package ui
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/JoaoPedr0Maciel/charm/internal/structs"
 	"github.com/fatih/color"
-	"github.com/tidwall/pretty"
 )
 
-func Display(display structs.Display) {
+const boxWidth = 77
+
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Auth-Token":        true,
+}
+
+func Display(display structs.Display) error {
+	if display.Stream != nil {
+		return displayStreamed(display)
+	}
+
+	switch display.Output {
+	case structs.OutputJSON:
+		return DisplayJSON(display)
+	case structs.OutputRaw:
+		return DisplayRaw(display)
+	}
+
 	fmt.Println()
-	DisplayHeader(display.Method, display.URL, display.Response, display.Body, display.TotalTime)
-	DisplayRequest(display.Method, display.URL, display.Request, display.AuthHeader, display.Data)
-	DisplayResponse(display.Response, display.Body, display.TotalTime)
+	DisplayHeader(display.Method, display.URL, display.Response, responseSize(display), display.TotalTime)
+	DisplayAttempts(display.Attempts)
+	DisplayRedirects(display.Redirects)
+	DisplayRequest(display)
+	if display.ShowTLS {
+		DisplayTLS(display.Timing.TLS)
+	}
+	DisplayResponse(display)
+	DisplayGraphQLErrors(display)
 	DisplayTiming(display.Timing, display.TotalTime)
+	DisplayAssertions(display.Assertions)
+	return nil
 }
 
-func DisplayHeader(method, url string, resp *http.Response, body []byte, totalTime time.Duration) {
+func DisplayHeader(method, url string, resp *http.Response, size int64, totalTime time.Duration) {
 	statusEmoji := GetEmojiByStatusCode(resp.StatusCode)
 	statusColor := GetColorByStatus(resp.StatusCode)
 
@@ -45,14 +76,15 @@ func DisplayHeader(method, url string, resp *http.Response, body []byte, totalTi
 	// Status, Time, Size
 	cyan.Printf("│ ")
 	color.New(statusColor, color.Bold).Printf("%s %d %s", statusEmoji, resp.StatusCode, http.StatusText(resp.StatusCode))
-	gray.Printf("  │  ⏱️  %s  │  📦 %s", totalTime.Round(time.Millisecond), FormatBytes(int64(len(body))))
-	fmt.Println(strings.Repeat(" ", max(0, 30-len(totalTime.Round(time.Millisecond).String())-len(FormatBytes(int64(len(body)))))) + "│")
+	gray.Printf("  │  ⏱️  %s  │  📦 %s", totalTime.Round(time.Millisecond), FormatBytes(size))
+	fmt.Println(strings.Repeat(" ", max(0, 30-len(totalTime.Round(time.Millisecond).String())-len(FormatBytes(size)))) + "│")
 
 	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
 	fmt.Println()
 }
 
-func DisplayRequest(method, url string, req *http.Request, authHeader string, data string) {
+func DisplayRequest(display structs.Display) {
+	method, url, req, data := display.Method, display.URL, display.Request, display.Data
 	cyan := color.New(color.FgHiCyan)
 	white := color.New(color.FgHiWhite)
 	gray := color.New(color.FgWhite)
@@ -66,24 +98,55 @@ func DisplayRequest(method, url string, req *http.Request, authHeader string, da
 	yellow.Print("│ URL:      ")
 	white.Println(truncateString(url, 65) + strings.Repeat(" ", max(0, 65-len(url))) + "│")
 
-	if req.Header.Get("Content-Type") != "" || authHeader != "" {
-		yellow.Println("│ Headers:  " + strings.Repeat(" ", 65) + "│")
+	if display.Proxy != nil {
+		proxy := display.Proxy.Redacted()
+		yellow.Print("│ Proxy:    ")
+		white.Println(truncateString(proxy, 65) + strings.Repeat(" ", max(0, 65-len(proxy))) + "│")
+	}
+
+	if query := req.URL.Query(); len(query) > 0 {
+		yellow.Println("│ Query:    " + strings.Repeat(" ", 65) + "│")
 
-		if ct := req.Header.Get("Content-Type"); ct != "" {
-			gray.Printf("│   • Content-Type: ")
-			white.Println(ct + strings.Repeat(" ", max(0, 57-len(ct))) + "│")
+		for _, key := range sortedKeys(query) {
+			for _, value := range query[key] {
+				printListItem(key, value)
+			}
 		}
+	}
+
+	if len(req.Header) > 0 {
+		yellow.Println("│ Headers:  " + strings.Repeat(" ", 65) + "│")
 
-		if authHeader != "" {
-			gray.Printf("│   • Authorization: ")
-			maskedAuth := MaskToken(authHeader)
-			white.Println(maskedAuth + strings.Repeat(" ", max(0, 56-len(maskedAuth))) + "│")
+		for _, name := range sortedKeys(req.Header) {
+			// Indexed directly: WebSocket handshakes use non-canonical keys
+			// such as Sec-WebSocket-Key.
+			for _, value := range req.Header[name] {
+				printListItem(name, MaskHeaderValue(name, value))
+			}
 		}
 	}
 
-	if data != "" {
+	if len(display.Form) > 0 {
 		yellow.Println("│ Body:     " + strings.Repeat(" ", 65) + "│")
-		bodyPreview := truncateString(data, 65)
+		for _, field := range display.Form {
+			if field.IsFile() {
+				printListItem(field.Name, fmt.Sprintf("📎 %s (%s, %s)", field.FileName, field.ContentType, FormatBytes(field.Size)))
+			} else {
+				printListItem(field.Name, field.Value)
+			}
+		}
+		printListItem("Total", FormatBytes(display.DataSize))
+	} else if display.DataFile != "" {
+		yellow.Println("│ Body:     " + strings.Repeat(" ", 65) + "│")
+		source := display.DataFile
+		if source == "-" {
+			source = "stdin"
+		}
+		printListItem("📄 "+source, FormatBytes(display.DataSize))
+	} else if data != "" {
+		yellow.Println("│ Body:     " + strings.Repeat(" ", 65) + "│")
+		// Collapse newlines so multi-line bodies (e.g. from .http files) fit on one line.
+		bodyPreview := truncateString(strings.Join(strings.Fields(data), " "), 65)
 		gray.Printf("│   ")
 		white.Println(bodyPreview + strings.Repeat(" ", max(0, 73-len(bodyPreview))) + "│")
 	}
@@ -92,7 +155,8 @@ func DisplayRequest(method, url string, req *http.Request, authHeader string, da
 	fmt.Println()
 }
 
-func DisplayResponse(resp *http.Response, body []byte, totalTime time.Duration) {
+func DisplayResponse(display structs.Display) {
+	resp, body, totalTime := display.Response, display.Body, display.TotalTime
 	statusEmoji := GetEmojiByStatusCode(resp.StatusCode)
 	statusColor := GetColorByStatus(resp.StatusCode)
 
@@ -113,12 +177,16 @@ func DisplayResponse(resp *http.Response, body []byte, totalTime time.Duration)
 	fmt.Println(strings.Repeat(" ", max(0, 65-len(totalTime.Round(time.Millisecond).String()))) + "│")
 
 	yellow.Print("│ Size:     ")
-	green.Print(FormatBytes(int64(len(body))))
-	fmt.Println(strings.Repeat(" ", max(0, 65-len(FormatBytes(int64(len(body)))))) + "│")
+	green.Print(FormatBytes(responseSize(display)))
+	fmt.Println(strings.Repeat(" ", max(0, 65-len(FormatBytes(responseSize(display))))) + "│")
 
 	yellow.Println("│ Headers:  " + strings.Repeat(" ", 65) + "│")
 
-	importantHeaders := []string{"Content-Type", "Content-Length", "Server", "Date", "X-Request-ID", "X-RateLimit-Remaining"}
+	importantHeaders := []string{
+		"Content-Type", "Content-Length", "Server", "Date", "X-Request-ID", "X-RateLimit-Remaining",
+		"Allow", "Cache-Control", "ETag", "Last-Modified", "Location",
+		"Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers",
+	}
 	for _, headerName := range importantHeaders {
 		if value := resp.Header.Get(headerName); value != "" {
 			gray.Printf("│   • %s: ", headerName)
@@ -129,23 +197,30 @@ func DisplayResponse(resp *http.Response, body []byte, totalTime time.Duration)
 
 	white.Println("│" + strings.Repeat(" ", 79) + "│")
 
-	yellow.Println("│ Body:     " + strings.Repeat(" ", 65) + "│")
+	if display.Filter != "" {
+		filter := truncateString(display.Filter, 65)
+		yellow.Print("│ Filter:   ")
+		white.Println(filter + strings.Repeat(" ", max(0, 67-visualLen(filter))) + "│")
+	}
 
-	if len(body) > 0 {
-		formatted := pretty.Pretty(body)
-		colored := pretty.Color(formatted, nil)
+	yellow.Println("│ Body:     " + strings.Repeat(" ", 65) + "│")
 
-		bodyLines := SplitLines(string(colored))
-		for _, line := range bodyLines {
-			if line != "" {
-				truncated := truncateString(line, 75)
-				fmt.Print("│ ")
-				fmt.Print(truncated)
-				fmt.Println(strings.Repeat(" ", max(0, 77-visualLen(line))) + "│")
-			}
+	if display.Filter != "" {
+		if len(display.FilterResults) == 0 {
+			gray.Println("│   (filter produced no results)" + strings.Repeat(" ", 46) + "│")
+		}
+		for _, result := range display.FilterResults {
+			printBody(jsonLines(encodeFilterResult(result)))
 		}
+	} else if display.Saved != nil {
+		printSavedFile(display.Saved)
+	} else if lines, ok := graphQLLines(display); ok {
+		printBody(lines)
+	} else if len(body) > 0 {
+		printBody(bodyLines(resp.Header.Get("Content-Type"), body))
 	} else {
-		gray.Println("│   (empty)" + strings.Repeat(" ", 66) + "│")
+		reason := emptyBodyReason(resp)
+		gray.Println("│   " + reason + strings.Repeat(" ", max(0, 73-visualLen(reason))) + "│")
 	}
 
 	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
@@ -178,6 +253,13 @@ func DisplayTiming(timing *structs.TimingInfo, totalTime time.Duration) {
 		fmt.Println(strings.Repeat(" ", max(0, 54-len(connectTime.Round(time.Millisecond).String()))) + "│")
 	}
 
+	if !timing.ProxyConnectStart.IsZero() && !timing.ProxyConnectDone.IsZero() {
+		proxyTime := timing.ProxyConnectDone.Sub(timing.ProxyConnectStart)
+		yellow.Print("│   • Proxy CONNECT: ")
+		cyan.Print(proxyTime.Round(time.Millisecond))
+		fmt.Println(strings.Repeat(" ", max(0, 54-len(proxyTime.Round(time.Millisecond).String()))) + "│")
+	}
+
 	if !timing.TLSStart.IsZero() && !timing.TLSDone.IsZero() {
 		yellow.Print("│   • TLS Handshake: ")
 		cyan.Print(tlsTime.Round(time.Millisecond))
@@ -198,6 +280,59 @@ func DisplayTiming(timing *structs.TimingInfo, totalTime time.Duration) {
 	fmt.Println()
 }
 
+// responseSize is the downloaded file size for -o/-O, else the body size.
+func responseSize(display structs.Display) int64 {
+	if display.Saved != nil {
+		return display.Saved.Size
+	}
+	return int64(len(display.Body))
+}
+
+func printSavedFile(saved *structs.SavedFile) {
+	green := color.New(color.FgHiGreen)
+	gray := color.New(color.FgWhite)
+
+	path := truncateString(saved.Path, 50)
+	line := fmt.Sprintf("💾 Saved to %s (%s)", path, FormatBytes(saved.Size))
+	fmt.Print("│   ")
+	green.Print(line)
+	fmt.Println(strings.Repeat(" ", max(0, boxWidth-3-visualLen(line)-1)) + "│")
+
+	if saved.ResumedFrom > 0 {
+		note := fmt.Sprintf("↻ resumed at %s, %s downloaded now", FormatBytes(saved.ResumedFrom), FormatBytes(saved.Size-saved.ResumedFrom))
+		if saved.Size == saved.ResumedFrom {
+			note = "✓ already complete, nothing left to download"
+		}
+		fmt.Print("│   ")
+		gray.Print(note)
+		fmt.Println(strings.Repeat(" ", max(0, boxWidth-3-visualLen(note))) + "│")
+	}
+}
+
+func printBody(lines []string) {
+	for _, line := range lines {
+		truncated := truncateVisible(line, bodyWidth)
+		fmt.Print("│ ")
+		fmt.Print(truncated)
+		fmt.Println(strings.Repeat(" ", max(1, boxWidth-1-visualLen(truncated))) + "│")
+	}
+}
+
+func emptyBodyReason(resp *http.Response) string {
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return "(no body: HEAD response)"
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return "(no body: 204 No Content)"
+	case http.StatusNotModified:
+		return "(no body: 304 Not Modified)"
+	}
+
+	return "(empty)"
+}
+
 func FormatBytes(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -217,14 +352,95 @@ func MaskToken(token string) string {
 		return token
 	}
 
-	tokenType := parts[0]
-	tokenValue := parts[1]
+	return parts[0] + " " + maskSecret(parts[1])
+}
+
+func MaskHeaderValue(name, value string) string {
+	if !sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+		return value
+	}
+
+	if strings.Contains(value, " ") {
+		return MaskToken(value)
+	}
+
+	return maskSecret(value)
+}
+
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("•", len(secret))
+	}
+
+	return strings.Repeat("•", len(secret)-4) + secret[len(secret)-4:]
+}
+
+func printListItem(name, value string) {
+	gray := color.New(color.FgWhite)
+	white := color.New(color.FgHiWhite)
+
+	prefix := fmt.Sprintf("│   • %s: ", name)
+	value = truncateString(value, max(4, boxWidth-visualLen(prefix)))
+	gray.Print(prefix)
+	white.Println(value + strings.Repeat(" ", max(0, boxWidth+1-visualLen(prefix)-visualLen(value))) + "│")
+}
+
+// printWrappedItem is like printListItem but wraps long values instead of truncating them.
+func printWrappedItem(name, value string) {
+	gray := color.New(color.FgWhite)
+	white := color.New(color.FgHiWhite)
+
+	prefix := fmt.Sprintf("│   • %s: ", name)
+	width := boxWidth + 1 - visualLen(prefix)
+	indent := "│" + strings.Repeat(" ", visualLen(prefix)-1)
+
+	for i, line := range wrapText(value, width) {
+		if i == 0 {
+			gray.Print(prefix)
+		} else {
+			gray.Print(indent)
+		}
+		white.Println(line + strings.Repeat(" ", max(0, width-visualLen(line))) + "│")
+	}
+}
+
+func wrapText(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for visualLen(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
 
-	if len(tokenValue) <= 8 {
-		return tokenType + " " + strings.Repeat("•", len(tokenValue))
+		switch {
+		case line == "":
+			line = word
+		case visualLen(line)+1+visualLen(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
 	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
 
-	return tokenType + " " + strings.Repeat("•", len(tokenValue)-4) + tokenValue[len(tokenValue)-4:]
+func sortedKeys[M ~map[string][]string](m M) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func truncateString(s string, maxLen int) string {
@@ -249,7 +465,7 @@ func stripAnsiCodes(s string) string {
 }
 
 func visualLen(s string) int {
-	return len(stripAnsiCodes(s))
+	return utf8.RuneCountInString(stripAnsiCodes(s))
 }
 
 func max(a, b int) int {
@@ -260,6 +476,10 @@ func max(a, b int) int {
 }
 
 func GetEmojiByStatusCode(statusCode int) string {
+	if statusCode >= 100 && statusCode < 200 {
+		return "🔄"
+	}
+
 	if statusCode >= 200 && statusCode < 300 {
 		return "✨"
 	}
@@ -276,6 +496,10 @@ func GetEmojiByStatusCode(statusCode int) string {
 }
 
 func GetColorByStatus(statusCode int) color.Attribute {
+	if statusCode >= 100 && statusCode < 200 {
+		return color.FgHiCyan
+	}
+
 	if statusCode >= 200 && statusCode < 300 {
 		return color.FgHiGreen
 	}
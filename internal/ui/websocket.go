@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/tidwall/pretty"
+)
+
+const frameHexPreviewSize = 64
+
+var closeCodeNames = map[int]string{
+	1000: "Normal Closure",
+	1001: "Going Away",
+	1002: "Protocol Error",
+	1003: "Unsupported Data",
+	1005: "No Status Received",
+	1006: "Abnormal Closure",
+	1007: "Invalid Payload Data",
+	1008: "Policy Violation",
+	1009: "Message Too Big",
+	1010: "Mandatory Extension",
+	1011: "Internal Error",
+	1012: "Service Restart",
+	1013: "Try Again Later",
+	1014: "Bad Gateway",
+	1015: "TLS Handshake",
+}
+
+type jsonWebSocketFrame struct {
+	Direction string   `json:"direction"`
+	Type      string   `json:"type"`
+	Data      any      `json:"data,omitempty"`
+	Size      int      `json:"size"`
+	CloseCode int      `json:"close_code,omitempty"`
+	RTTMs     *float64 `json:"rtt_ms,omitempty"`
+	ElapsedMs float64  `json:"elapsed_ms"`
+}
+
+type jsonWebSocketSummary struct {
+	Type          string  `json:"type"`
+	CloseCode     int     `json:"close_code"`
+	CloseReason   string  `json:"close_reason,omitempty"`
+	ClosedBy      string  `json:"closed_by,omitempty"`
+	Sent          int     `json:"sent"`
+	Received      int     `json:"received"`
+	BytesSent     int64   `json:"bytes_sent"`
+	BytesReceived int64   `json:"bytes_received"`
+	DurationMs    float64 `json:"duration_ms"`
+}
+
+// DisplayWebSocketOpen shows the handshake like an HTTP response, followed by
+// the negotiated connection details.
+func DisplayWebSocketOpen(display structs.Display, subprotocol string, interactive bool) {
+	if display.Output != structs.OutputPretty {
+		return
+	}
+
+	resp := display.Response
+	cyan := color.New(color.FgHiCyan)
+	white := color.New(color.FgHiWhite)
+
+	fmt.Println()
+	DisplayHeader(display.Method, display.URL, resp, 0, display.TotalTime)
+	DisplayRequest(display)
+	if display.ShowTLS {
+		DisplayTLS(display.Timing.TLS)
+	}
+
+	cyan.Println("╭─ 🔌 WEBSOCKET ──────────────────────────────────────────────────────────────╮")
+	printListItem("Handshake", display.TotalTime.Round(time.Millisecond).String())
+	printListItem("Subprotocol", valueOr(subprotocol, "none"))
+	if extensions := resp.Header.Get("Sec-WebSocket-Extensions"); extensions != "" {
+		printListItem("Extensions", extensions)
+	}
+	if server := resp.Header.Get("Server"); server != "" {
+		printListItem("Server", server)
+	}
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+
+	if interactive && isatty.IsTerminal(os.Stdin.Fd()) {
+		color.New(color.FgWhite).Fprintln(os.Stderr, "Type a message and press Enter to send it (@ping, @binary <hex>, @close [code] [reason]); Ctrl+D closes.")
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// DisplayWebSocketFrame prints one frame as soon as it is sent or received.
+// In json mode each frame is one compact line; raw mode prints only the
+// payloads received.
+func DisplayWebSocketFrame(frame structs.WebSocketFrame, output string) {
+	switch output {
+	case structs.OutputJSON:
+		data, _ := json.Marshal(buildJSONFrame(frame))
+		fmt.Println(string(data))
+		return
+	case structs.OutputRaw:
+		if !frame.Sent && (frame.Type == structs.FrameText || frame.Type == structs.FrameBinary) {
+			os.Stdout.Write(frame.Data)
+			if frame.Type == structs.FrameText {
+				fmt.Println()
+			}
+		}
+		return
+	}
+
+	gray := color.New(color.FgWhite)
+	arrow := color.New(color.FgHiCyan).Sprint("←")
+	if frame.Sent {
+		arrow = color.New(color.FgHiGreen).Sprint("→")
+	}
+
+	gray.Printf("%9s ", fmt.Sprintf("+%.3fs", frame.Elapsed.Seconds()))
+	fmt.Print(arrow + " ")
+	color.New(frameColor(frame.Type), color.Bold).Printf("%-6s ", frame.Type)
+
+	indent := strings.Repeat(" ", 19)
+	switch frame.Type {
+	case structs.FrameText:
+		lines := frameTextLines(frame.Data)
+		fmt.Println(lines[0])
+		for _, line := range lines[1:] {
+			fmt.Println(indent + line)
+		}
+	case structs.FrameBinary:
+		gray.Println(FormatBytes(int64(len(frame.Data))))
+		for _, line := range hexDumpLines(frame.Data, frameHexPreviewSize) {
+			fmt.Println(indent + line)
+		}
+	case structs.FrameClose:
+		fmt.Print(closeCodeText(frame.CloseCode))
+		if len(frame.Data) > 0 {
+			gray.Printf("  %q", frame.Data)
+		}
+		fmt.Println()
+	default:
+		gray.Print(string(frame.Data))
+		if frame.RTT > 0 {
+			color.New(color.FgHiGreen).Printf("  rtt %s", frame.RTT.Round(time.Microsecond*100))
+		}
+		fmt.Println()
+	}
+}
+
+// DisplayWebSocketError reports an invalid interactive line on stderr.
+func DisplayWebSocketError(err error) {
+	color.New(color.FgYellow).Fprintf(os.Stderr, "✗ %s\n", err)
+}
+
+// DisplayWebSocketClose summarizes the session and how it was closed.
+func DisplayWebSocketClose(summary structs.WebSocketSummary, output string) {
+	switch output {
+	case structs.OutputJSON:
+		data, _ := json.Marshal(jsonWebSocketSummary{
+			Type:          "summary",
+			CloseCode:     summary.CloseCode,
+			CloseReason:   summary.CloseReason,
+			ClosedBy:      summary.ClosedBy,
+			Sent:          summary.Sent,
+			Received:      summary.Received,
+			BytesSent:     summary.BytesSent,
+			BytesReceived: summary.BytesReceived,
+			DurationMs:    milliseconds(summary.Duration),
+		})
+		fmt.Println(string(data))
+		return
+	case structs.OutputRaw:
+		return
+	}
+
+	white := color.New(color.FgHiWhite)
+
+	fmt.Println()
+	switch summary.ClosedBy {
+	case "":
+		color.New(color.FgHiRed).Println("╭─ ⚠️  DISCONNECTED ──────────────────────────────────────────────────────────╮")
+	default:
+		color.New(color.FgHiGreen).Println("╭─ 🔒 CLOSED ─────────────────────────────────────────────────────────────────╮")
+	}
+
+	printListItem("Close code", closeCodeText(summary.CloseCode))
+	if summary.CloseReason != "" {
+		printWrappedItem("Reason", summary.CloseReason)
+	}
+	printListItem("Closed by", valueOr(summary.ClosedBy, "connection dropped without a close frame"))
+	printListItem("Duration", summary.Duration.Round(time.Millisecond).String())
+	printListItem("Sent", fmt.Sprintf("%d message(s), %s", summary.Sent, FormatBytes(summary.BytesSent)))
+	printListItem("Received", fmt.Sprintf("%d message(s), %s", summary.Received, FormatBytes(summary.BytesReceived)))
+	if summary.Pings > 0 {
+		pings := fmt.Sprintf("%d sent, %d answered", summary.Pings, summary.Pongs)
+		if summary.Pongs > 0 {
+			pings += ", avg RTT " + (summary.TotalRTT / time.Duration(summary.Pongs)).Round(time.Microsecond*100).String()
+		}
+		printListItem("Pings", pings)
+	}
+
+	white.Println("╰─────────────────────────────────────────────────────────────────────────────╯")
+	fmt.Println()
+}
+
+func buildJSONFrame(frame structs.WebSocketFrame) jsonWebSocketFrame {
+	direction := "received"
+	if frame.Sent {
+		direction = "sent"
+	}
+
+	result := jsonWebSocketFrame{
+		Direction: direction,
+		Type:      frame.Type,
+		Size:      len(frame.Data),
+		CloseCode: frame.CloseCode,
+		ElapsedMs: milliseconds(frame.Elapsed),
+	}
+
+	switch {
+	case len(frame.Data) == 0:
+	case frame.Type == structs.FrameText:
+		result.Data = jsonBody(frame.Data)
+	case frame.Type == structs.FrameBinary:
+		result.Data = hex.EncodeToString(frame.Data)
+	default:
+		result.Data = string(frame.Data)
+	}
+
+	if frame.RTT > 0 {
+		rtt := milliseconds(frame.RTT)
+		result.RTTMs = &rtt
+	}
+
+	return result
+}
+
+// frameTextLines keeps JSON messages on a single colored line, like SSE data.
+func frameTextLines(data []byte) []string {
+	if json.Valid(data) {
+		return []string{string(pretty.Color(pretty.Ugly(data), nil))}
+	}
+	if lines := SplitLines(string(data)); len(lines) > 0 {
+		return lines
+	}
+	return []string{""}
+}
+
+func frameColor(frameType string) color.Attribute {
+	switch frameType {
+	case structs.FrameBinary:
+		return color.FgHiMagenta
+	case structs.FramePing, structs.FramePong:
+		return color.FgWhite
+	case structs.FrameClose:
+		return color.FgYellow
+	}
+	return color.FgHiWhite
+}
+
+func closeCodeText(code int) string {
+	if name, ok := closeCodeNames[code]; ok {
+		return fmt.Sprintf("%d %s", code, name)
+	}
+	if code >= 4000 {
+		return fmt.Sprintf("%d (application defined)", code)
+	}
+	return fmt.Sprint(code)
+}
@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/graphql"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/itchyny/gojq"
+)
+
+// ParseExpectStatus validates an --expect-status list such as "201" or "200,204,3xx".
+func ParseExpectStatus(value string) ([]string, error) {
+	var conditions []string
+	for _, condition := range strings.Split(value, ",") {
+		condition = strings.ToLower(strings.TrimSpace(condition))
+		if condition == "" {
+			continue
+		}
+		if !isStatusCondition(condition) {
+			return nil, fmt.Errorf("invalid --expect-status %q: expected a status code (201) or a class (2xx)", condition)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// ParseExpectHeaders parses --expect-header values: 'Name: value' or just 'Name'.
+func ParseExpectHeaders(values []string) ([]structs.HeaderExpectation, error) {
+	var expectations []structs.HeaderExpectation
+	for _, value := range values {
+		name, expected, _ := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid --expect-header %q: expected format 'Name: value' or 'Name'", value)
+		}
+		expectations = append(expectations, structs.HeaderExpectation{
+			Name:  http.CanonicalHeaderKey(name),
+			Value: strings.TrimSpace(expected),
+		})
+	}
+	return expectations, nil
+}
+
+// ParseExpectJSON validates --expect-json jq expressions such as
+// '.data.id != null' or '.items | length > 0'.
+func ParseExpectJSON(values []string) ([]string, error) {
+	var expectations []string
+	for _, value := range values {
+		expr := strings.TrimSpace(value)
+		if _, err := compileJQ("--expect-json", expr); err != nil {
+			return nil, err
+		}
+		expectations = append(expectations, expr)
+	}
+	return expectations, nil
+}
+
+// ParseExpectTime parses --expect-time values such as "<500ms" or "2s".
+func ParseExpectTime(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	limit, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "<")))
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid --expect-time %q: expected a duration such as <500ms", value)
+	}
+	return limit, nil
+}
+
+func evaluateExpectations(expect structs.Expectations, resp *http.Response, body []byte, totalTime time.Duration) []structs.Assertion {
+	var assertions []structs.Assertion
+	status := strconv.Itoa(resp.StatusCode)
+
+	if expect.Fail {
+		assertions = append(assertions, structs.Assertion{
+			Name:   "status < 400 (--fail)",
+			Passed: resp.StatusCode < 400,
+			Actual: status,
+		})
+	}
+
+	if len(expect.Status) > 0 {
+		passed := false
+		for _, condition := range expect.Status {
+			passed = passed || matchesStatus(condition, resp.StatusCode)
+		}
+		assertions = append(assertions, structs.Assertion{
+			Name:   "status " + strings.Join(expect.Status, " or "),
+			Passed: passed,
+			Actual: status,
+		})
+	}
+
+	for _, header := range expect.Headers {
+		assertions = append(assertions, evaluateHeader(header, resp.Header))
+	}
+
+	for _, expectation := range expect.JSON {
+		assertions = append(assertions, evaluateJSON(expectation, body))
+	}
+
+	if expect.NoGraphQLErrors {
+		assertions = append(assertions, evaluateGraphQLErrors(body))
+	}
+
+	if expect.MaxTime > 0 {
+		assertions = append(assertions, structs.Assertion{
+			Name:   "time < " + expect.MaxTime.String(),
+			Passed: totalTime < expect.MaxTime,
+			Actual: totalTime.Round(time.Millisecond).String(),
+		})
+	}
+
+	return assertions
+}
+
+func evaluateHeader(expectation structs.HeaderExpectation, header http.Header) structs.Assertion {
+	assertion := structs.Assertion{Name: "header " + expectation.Name, Actual: "missing"}
+	if expectation.Value != "" {
+		assertion.Name += ": " + expectation.Value
+	}
+
+	values := header.Values(expectation.Name)
+	if len(values) == 0 {
+		return assertion
+	}
+
+	assertion.Actual = strings.Join(values, ", ")
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), strings.ToLower(expectation.Value)) {
+			assertion.Passed = true
+		}
+	}
+	return assertion
+}
+
+// evaluateJSON runs an --expect-json expression over the body; it passes
+// when every result is true, so '.token' alone fails while '.token != null'
+// checks that the token is set.
+func evaluateJSON(expr string, body []byte) structs.Assertion {
+	assertion := structs.Assertion{Name: expr}
+
+	code, err := compileJQ("--expect-json", expr)
+	if err != nil {
+		assertion.Actual = err.Error()
+		return assertion
+	}
+
+	input, err := decodeJQInput(body)
+	if err != nil {
+		assertion.Actual = "body is not JSON"
+		return assertion
+	}
+
+	results, err := runJQ(context.Background(), code, input)
+	if err != nil {
+		assertion.Actual = err.Error()
+		return assertion
+	}
+	if len(results) == 0 {
+		assertion.Actual = "no result"
+		return assertion
+	}
+
+	actual := make([]string, len(results))
+	assertion.Passed = true
+	for i, result := range results {
+		assertion.Passed = assertion.Passed && result == true
+		encoded, _ := gojq.Marshal(result)
+		actual[i] = string(encoded)
+	}
+	assertion.Actual = strings.Join(actual, ", ")
+	return assertion
+}
+
+func failedAssertions(assertions []structs.Assertion) int {
+	failed := 0
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// evaluateGraphQLErrors fails when the body has a non-empty "errors" list, which
+// GraphQL servers usually send with a 200 status.
+func evaluateGraphQLErrors(body []byte) structs.Assertion {
+	assertion := structs.Assertion{Name: "no GraphQL errors (--fail)"}
+
+	resp, err := graphql.ParseResponse(body)
+	switch {
+	case err != nil:
+		assertion.Actual = "not a GraphQL response"
+	case len(resp.Errors) > 0:
+		assertion.Actual = fmt.Sprintf("%d error(s): %s", len(resp.Errors), resp.Errors[0].Message)
+	default:
+		assertion.Passed = true
+		assertion.Actual = "none"
+	}
+
+	return assertion
+}
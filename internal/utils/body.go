@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+const (
+	FileDataPrefix = "@"
+	StdinDataFile  = "-"
+)
+
+type requestBody struct {
+	reader        io.Reader
+	counter       *countingReader
+	contentType   string
+	contentLength int64
+	// forceContentType is set when the body is only valid with its own
+	// Content-Type (e.g. the multipart boundary), overriding user values.
+	forceContentType bool
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+func (c *countingReader) Close() error {
+	if closer, ok := c.reader.(io.Closer); ok && c.reader != os.Stdin {
+		return closer.Close()
+	}
+	return nil
+}
+
+func ParseDataArg(data string) (string, string) {
+	if strings.HasPrefix(data, FileDataPrefix) {
+		return "", strings.TrimPrefix(data, FileDataPrefix)
+	}
+	return data, ""
+}
+
+func newRequestBody(opts structs.RequestOptions) (*requestBody, error) {
+	if len(opts.Form) > 0 {
+		return newFormBody(opts.Form)
+	}
+
+	if opts.DataFile != "" {
+		return newFileBody(opts.DataFile)
+	}
+
+	if opts.Data != "" {
+		return &requestBody{
+			reader:        strings.NewReader(opts.Data),
+			contentLength: int64(len(opts.Data)),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func newFileBody(path string) (*requestBody, error) {
+	if path == StdinDataFile {
+		counter := &countingReader{reader: os.Stdin}
+		return &requestBody{reader: counter, counter: counter, contentLength: -1}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	counter := &countingReader{reader: file}
+	return &requestBody{
+		reader:        counter,
+		counter:       counter,
+		contentType:   mime.TypeByExtension(filepath.Ext(path)),
+		contentLength: info.Size(),
+	}, nil
+}
+
+func (b *requestBody) size() int64 {
+	if b == nil {
+		return 0
+	}
+	if b.counter != nil {
+		return b.counter.count
+	}
+	return b.contentLength
+}
+
+func (b *requestBody) close() {
+	if b != nil && b.counter != nil {
+		b.counter.Close()
+	}
+}
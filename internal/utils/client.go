@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+// newAttemptContext bounds a single attempt by --max-time.
+func newAttemptContext(ctx context.Context, opts structs.RequestOptions) (context.Context, context.CancelFunc) {
+	if opts.MaxTime <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, opts.MaxTime)
+}
+
+func newSignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+func newHTTPClient(opts structs.RequestOptions) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.ResponseHeaderTimeout = opts.Timeout
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFor(opts, req.URL)
+	}
+	transport.OnProxyConnectResponse = onProxyConnectResponse
+
+	if opts.ConnectTimeout > 0 {
+		dialer := &net.Dialer{Timeout: opts.ConnectTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		// Redirects are followed by doAttempt so every hop can be timed and displayed.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+func newFailure(ctx context.Context, err error, timing *structs.TimingInfo, opts structs.RequestOptions) *structs.Failure {
+	failure := &structs.Failure{
+		Kind:  structs.FailureError,
+		Phase: timing.Phase(),
+		Hint:  tlsHint(err),
+		Err:   err,
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		failure.Kind = structs.FailureTimeout
+		failure.Limit = "--max-time " + opts.MaxTime.String()
+	case errors.Is(ctx.Err(), context.Canceled):
+		failure.Kind = structs.FailureInterrupted
+	case isTimeout(err):
+		failure.Kind = structs.FailureTimeout
+		failure.Limit = timeoutFlag(failure.Phase, opts)
+	}
+
+	return failure
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func timeoutFlag(phase string, opts structs.RequestOptions) string {
+	switch phase {
+	case structs.PhaseDNS, structs.PhaseConnect, structs.PhaseProxy, structs.PhaseTLS:
+		if opts.ConnectTimeout > 0 {
+			return "--connect-timeout " + opts.ConnectTimeout.String()
+		}
+	case structs.PhaseWaiting:
+		if opts.Timeout > 0 {
+			return "--timeout " + opts.Timeout.String()
+		}
+	}
+	return ""
+}
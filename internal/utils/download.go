@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+)
+
+const defaultDownloadName = "index.html"
+
+func isDownload(opts structs.RequestOptions) bool {
+	return opts.OutputFile != "" || opts.RemoteName
+}
+
+// downloadPath picks the target file: -o as given, or for -O the
+// Content-Disposition filename, falling back to the last URL segment.
+func downloadPath(opts structs.RequestOptions, resp *http.Response, requestURL *url.URL) string {
+	if opts.OutputFile != "" {
+		return opts.OutputFile
+	}
+
+	if resp != nil {
+		if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+			if name := safeFileName(params["filename"]); name != "" {
+				return name
+			}
+		}
+	}
+
+	if name := safeFileName(path.Base(requestURL.Path)); name != "" {
+		return name
+	}
+	return defaultDownloadName
+}
+
+// safeFileName keeps only the base name so a server cannot write outside
+// the current directory.
+func safeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == ".." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// setResumeRange asks for the rest of a partially downloaded file and
+// returns how many bytes are already on disk. With -O the file is found by
+// its URL name, since Content-Disposition is unknown before the response.
+func setResumeRange(req *http.Request, opts structs.RequestOptions) int64 {
+	if !opts.Resume || !isDownload(opts) {
+		return 0
+	}
+
+	info, err := os.Stat(downloadPath(opts, nil, req.URL))
+	if err != nil || info.Size() == 0 {
+		return 0
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", info.Size()))
+	return info.Size()
+}
+
+// saveResponseBody streams a successful response to disk with a progress bar.
+// A 206 appends to the existing file; a 200 means the server ignored the
+// Range header, so the file is rewritten from the start.
+func saveResponseBody(resp *http.Response, opts structs.RequestOptions, resumeFrom int64) (*structs.SavedFile, error) {
+	defer resp.Body.Close()
+
+	target := downloadPath(opts, resp, resp.Request.URL)
+	if resumeFrom > 0 {
+		// The offset was measured on the file named before the response
+		// arrived; appending to a Content-Disposition name would corrupt it.
+		target = downloadPath(opts, nil, resp.Request.URL)
+	}
+	saved := &structs.SavedFile{Path: target}
+
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && resumeFrom > 0 {
+		// Nothing left to fetch: the file on disk is already complete.
+		saved.Size, saved.ResumedFrom = resumeFrom, resumeFrom
+		return saved, nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resp.StatusCode == http.StatusPartialContent && resumeFrom > 0 {
+		start, err := contentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil || start != resumeFrom {
+			return nil, fmt.Errorf("server resumed at an unexpected offset (Content-Range %q, expected byte %d)", resp.Header.Get("Content-Range"), resumeFrom)
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		saved.ResumedFrom = resumeFrom
+	}
+
+	file, err := os.OpenFile(target, flags, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = saved.ResumedFrom + resp.ContentLength
+	}
+
+	progress := ui.NewProgress(target, saved.ResumedFrom, total)
+	written, err := io.Copy(file, io.TeeReader(resp.Body, progress))
+	progress.Finish()
+
+	saved.Size = saved.ResumedFrom + written
+	return saved, err
+}
+
+// contentRangeStart reads START from "bytes START-END/TOTAL".
+func contentRangeStart(header string) (int64, error) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range")
+	}
+	start, _, _ := strings.Cut(spec, "-")
+	return strconv.ParseInt(start, 10, 64)
+}
@@ -0,0 +1,31 @@
+package utils
+
+import "fmt"
+
+// Exit codes that let CI tell a broken connection from a failed check.
+const (
+	ExitTransport = 2
+	ExitAssertion = 3
+)
+
+// TransportError means no complete response was received (DNS, connect,
+// TLS, timeout or interruption).
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string { return e.Err.Error() }
+func (e *TransportError) Unwrap() error { return e.Err }
+func (e *TransportError) ExitCode() int { return ExitTransport }
+
+// AssertionError means the response arrived but --fail or an --expect-* check failed.
+type AssertionError struct {
+	Failed int
+	Total  int
+}
+
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("%d of %d assertions failed", e.Failed, e.Total)
+}
+
+func (e *AssertionError) ExitCode() int { return ExitAssertion }
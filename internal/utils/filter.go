@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/itchyny/gojq"
+)
+
+// CompileFilter parses a --filter jq expression. Parse errors point at the
+// offending position:
+//
+//	invalid --filter: unexpected token "}"
+//	  .items[] | {id, }
+//	                  ^
+func CompileFilter(expr string) (*gojq.Code, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return compileJQ("--filter", expr)
+}
+
+// compiledJQ caches compiled expressions, so validating a flag up front and
+// running it later (or once per collection step) compiles it only once.
+var compiledJQ sync.Map
+
+// compileJQ compiles the jq expression given to flag, reporting errors
+// against it.
+func compileJQ(flag, expr string) (*gojq.Code, error) {
+	if code, ok := compiledJQ.Load(expr); ok {
+		return code.(*gojq.Code), nil
+	}
+
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		var parseErr *gojq.ParseError
+		if errors.As(err, &parseErr) {
+			position := max(0, parseErr.Offset-len(parseErr.Token))
+			return nil, fmt.Errorf("invalid %s: %s\n  %s\n  %s^", flag, err, expr, strings.Repeat(" ", position))
+		}
+		return nil, fmt.Errorf("invalid %s: %w", flag, err)
+	}
+
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", flag, expr, err)
+	}
+
+	compiledJQ.Store(expr, code)
+	return code, nil
+}
+
+// filterBody runs the filter over a JSON body and collects every result, as
+// jq would print them one after another.
+func filterBody(ctx context.Context, code *gojq.Code, body []byte) ([]any, error) {
+	input, err := decodeJQInput(body)
+	if err != nil {
+		return nil, fmt.Errorf("--filter needs a JSON response body: %w", err)
+	}
+
+	results, err := runJQ(ctx, code, input)
+	if err != nil {
+		return nil, fmt.Errorf("--filter failed: %w", err)
+	}
+	return results, nil
+}
+
+// decodeJQInput decodes a JSON body keeping numbers exact, as gojq expects.
+func decodeJQInput(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var input any
+	if err := decoder.Decode(&input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+func runJQ(ctx context.Context, code *gojq.Code, input any) ([]any, error) {
+	var results []any
+	iter := code.RunWithContext(ctx, input)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			return nil, err
+		}
+		results = append(results, value)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+const (
+	FormContentType      = "application/x-www-form-urlencoded"
+	DefaultFileMediaType = "application/octet-stream"
+)
+
+// ParseFormFields parses --form "key=value" and --file "field=@path[;type=mime][;filename=name]" flags.
+func ParseFormFields(form, files []string) ([]structs.FormField, error) {
+	fields := make([]structs.FormField, 0, len(form)+len(files))
+
+	for _, value := range form {
+		name, fieldValue, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid form field %q: expected format 'key=value'", value)
+		}
+		fields = append(fields, structs.FormField{Name: name, Value: fieldValue})
+	}
+
+	for _, value := range files {
+		field, err := parseFileField(value)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func parseFileField(value string) (structs.FormField, error) {
+	name, spec, ok := strings.Cut(value, "=")
+	if !ok || name == "" || spec == "" {
+		return structs.FormField{}, fmt.Errorf("invalid file field %q: expected format 'field=@path'", value)
+	}
+
+	parts := strings.Split(spec, ";")
+	field := structs.FormField{
+		Name:     name,
+		FilePath: strings.TrimPrefix(parts[0], FileDataPrefix),
+	}
+
+	for _, option := range parts[1:] {
+		key, optionValue, _ := strings.Cut(option, "=")
+		switch strings.TrimSpace(key) {
+		case "type":
+			field.ContentType = optionValue
+		case "filename":
+			field.FileName = optionValue
+		default:
+			return structs.FormField{}, fmt.Errorf("invalid file field %q: unknown option %q", value, key)
+		}
+	}
+
+	info, err := os.Stat(field.FilePath)
+	if err != nil {
+		return structs.FormField{}, fmt.Errorf("invalid file field %q: %w", value, err)
+	}
+	if info.IsDir() {
+		return structs.FormField{}, fmt.Errorf("invalid file field %q: %s is a directory", value, field.FilePath)
+	}
+	field.Size = info.Size()
+
+	if field.FileName == "" {
+		field.FileName = filepath.Base(field.FilePath)
+	}
+	if field.ContentType == "" {
+		field.ContentType = mime.TypeByExtension(filepath.Ext(field.FilePath))
+	}
+	if field.ContentType == "" {
+		field.ContentType = DefaultFileMediaType
+	}
+
+	return field, nil
+}
+
+func newFormBody(fields []structs.FormField) (*requestBody, error) {
+	if !hasFileField(fields) {
+		values := url.Values{}
+		for _, field := range fields {
+			values.Add(field.Name, field.Value)
+		}
+		encoded := values.Encode()
+		return &requestBody{
+			reader:        strings.NewReader(encoded),
+			contentType:   FormContentType,
+			contentLength: int64(len(encoded)),
+		}, nil
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	contentLength, err := multipartLength(fields, boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+	writer.SetBoundary(boundary)
+
+	go func() {
+		err := writeMultipart(writer, fields, true)
+		if err == nil {
+			err = writer.Close()
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+
+	counter := &countingReader{reader: pipeReader}
+	return &requestBody{
+		reader:           counter,
+		counter:          counter,
+		contentType:      writer.FormDataContentType(),
+		contentLength:    contentLength,
+		forceContentType: true,
+	}, nil
+}
+
+// multipartLength computes the exact encoded size without reading the files,
+// so uploads are sent with a Content-Length instead of chunked encoding.
+func multipartLength(fields []structs.FormField, boundary string) (int64, error) {
+	counter := &countingWriter{}
+	writer := multipart.NewWriter(counter)
+	writer.SetBoundary(boundary)
+
+	if err := writeMultipart(writer, fields, false); err != nil {
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+
+	total := counter.count
+	for _, field := range fields {
+		if field.IsFile() {
+			total += field.Size
+		}
+	}
+	return total, nil
+}
+
+func writeMultipart(writer *multipart.Writer, fields []structs.FormField, withContent bool) error {
+	for _, field := range fields {
+		if !field.IsFile() {
+			if err := writer.WriteField(field.Name, field.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     field.Name,
+			"filename": field.FileName,
+		}))
+		header.Set("Content-Type", field.ContentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		if withContent {
+			if err := copyFile(part, field.FilePath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyFile(dst io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(dst, file)
+	return err
+}
+
+func hasFileField(fields []structs.FormField) bool {
+	for _, field := range fields {
+		if field.IsFile() {
+			return true
+		}
+	}
+	return false
+}
+
+type countingWriter struct {
+	count int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.count += int64(len(p))
+	return len(p), nil
+}
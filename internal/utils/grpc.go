@@ -0,0 +1,422 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/protoschema"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPC calls a gRPC method, or lists the services with --list. The schema
+// comes from the .proto files given with --proto, else from server
+// reflection; messages are transcoded from and to JSON.
+func GRPC(opts structs.RequestOptions, grpcOpts structs.GRPCOptions) error {
+	if grpcOpts.Web {
+		return grpcWeb(opts, grpcOpts)
+	}
+
+	target, err := grpcTarget(opts.URL, grpcOpts.Plaintext)
+	if err != nil {
+		return err
+	}
+	opts.URL = target
+
+	ctx, cancel := newSignalContext()
+	defer cancel()
+
+	callCtx, cancelCall := newAttemptContext(ctx, opts)
+	defer cancelCall()
+
+	timing := &structs.TimingInfo{}
+	conn, err := dialGRPC(opts, grpcOpts, timing)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	display := structs.NewDisplay("gRPC", target).
+		WithTLSInfo(opts.TLSInfo).
+		WithOutput(opts.Output)
+	start := time.Now()
+
+	schema, err := loadGRPCSchema(callCtx, conn, grpcOpts)
+	if err != nil {
+		if isGRPCTransportError(callCtx, err) {
+			display.WithTiming(time.Since(start), timing)
+			return &TransportError{Err: displayFailure(display, newFailure(callCtx, err, timing, opts))}
+		}
+		return err
+	}
+
+	if grpcOpts.List {
+		return ui.DisplayGRPCServices(schema.Services(), opts.Output)
+	}
+
+	method, err := schema.FindMethod(grpcOpts.Method)
+	if err != nil {
+		return err
+	}
+
+	requests, err := decodeGRPCMessages(opts.Data, method, schema.Resolver())
+	if err != nil {
+		return err
+	}
+
+	call := newGRPCCall(opts, method)
+	call.Target, call.Secure = target, !grpcOpts.Plaintext
+	call.Live = method.IsStreamingServer()
+	call.Requests = encodeGRPCMessages(requests, schema.Resolver())
+	ui.DisplayGRPCStart(*call)
+
+	err = invokeGRPC(callCtx, conn, method, requests, call, timing, schema.Resolver())
+	// The total includes the connection and the reflection lookup, which
+	// usually opens the connection before the call starts.
+	call.TotalTime = time.Since(start)
+	if err != nil && call.Headers == nil && isGRPCTransportError(callCtx, err) {
+		display.WithTiming(call.TotalTime, timing)
+		return &TransportError{Err: displayFailure(display, newFailure(callCtx, err, timing, opts))}
+	}
+
+	return displayGRPCCall(call, opts)
+}
+
+// grpcTarget checks the host:port argument; the port defaults to 443, or 80
+// with --plaintext.
+func grpcTarget(target string, plaintext bool) (string, error) {
+	if strings.Contains(target, "://") {
+		return "", fmt.Errorf("invalid target %q: use host:port (URLs are only accepted with --web)", target)
+	}
+	if target == "" {
+		return "", fmt.Errorf("invalid target: use host:port")
+	}
+
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		port := "443"
+		if plaintext {
+			port = "80"
+		}
+		return net.JoinHostPort(strings.Trim(target, "[]"), port), nil
+	}
+	return target, nil
+}
+
+func dialGRPC(opts structs.RequestOptions, grpcOpts structs.GRPCOptions, timing *structs.TimingInfo) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if !grpcOpts.Plaintext {
+		tlsConfig, err := newTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+		creds = timedCredentials{TransportCredentials: credentials.NewTLS(tlsConfig), timing: timing}
+	}
+
+	// passthrough hands the host name to the dialer, which resolves it
+	// itself so the DNS lookup can be timed.
+	conn, err := grpc.NewClient("passthrough:///"+opts.URL,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(timedDialer(opts.ConnectTimeout, timing)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+	return conn, nil
+}
+
+func timedDialer(timeout time.Duration, timing *structs.TimingInfo) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses := []string{host}
+		if net.ParseIP(host) == nil {
+			timing.DNSStart = time.Now()
+			addresses, err = net.DefaultResolver.LookupHost(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			timing.DNSDone = time.Now()
+		}
+
+		dialer := &net.Dialer{Timeout: timeout}
+		timing.ConnectStart = time.Now()
+		for _, address := range addresses {
+			var conn net.Conn
+			conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, port))
+			if err == nil {
+				timing.ConnectDone = time.Now()
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// timedCredentials records the TLS handshake like createClientTrace does for
+// HTTP requests.
+type timedCredentials struct {
+	credentials.TransportCredentials
+	timing *structs.TimingInfo
+}
+
+func (c timedCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	c.timing.TLSStart = time.Now()
+	conn, info, err := c.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	if err != nil {
+		c.timing.TLS = unverifiedState(err)
+		return conn, info, err
+	}
+	if tlsInfo, ok := info.(credentials.TLSInfo); ok {
+		state := tlsInfo.State
+		c.timing.TLS = &state
+	}
+	c.timing.TLSDone = time.Now()
+	return conn, info, err
+}
+
+func (c timedCredentials) Clone() credentials.TransportCredentials {
+	return timedCredentials{TransportCredentials: c.TransportCredentials.Clone(), timing: c.timing}
+}
+
+func loadGRPCSchema(ctx context.Context, conn grpc.ClientConnInterface, grpcOpts structs.GRPCOptions) (*protoschema.Schema, error) {
+	if len(grpcOpts.ProtoFiles) > 0 {
+		return protoschema.FromProtoFiles(ctx, grpcOpts.ProtoFiles, grpcOpts.ImportPaths)
+	}
+
+	service := ""
+	if !grpcOpts.List {
+		name, _, err := protoschema.ParseMethodName(grpcOpts.Method)
+		if err != nil {
+			return nil, err
+		}
+		service = name
+	}
+	return protoschema.FromReflection(ctx, conn, service)
+}
+
+// isGRPCTransportError tells connection failures, --max-time and Ctrl+C
+// apart from statuses returned by the server.
+func isGRPCTransportError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// decodeGRPCMessages turns --data into request messages: one JSON object per
+// message, so client streaming calls take several objects in a row. Without
+// --data a unary call sends an empty message.
+func decodeGRPCMessages(data string, method protoreflect.MethodDescriptor, resolver protoschema.Resolver) ([]proto.Message, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	unmarshal := protojson.UnmarshalOptions{Resolver: resolver}
+
+	var messages []proto.Message
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid --data: %w", err)
+		}
+
+		message := dynamicpb.NewMessage(method.Input())
+		if err := unmarshal.Unmarshal(raw, message); err != nil {
+			return nil, fmt.Errorf("invalid --data for %s: %w", method.Input().FullName(), err)
+		}
+		messages = append(messages, message)
+	}
+
+	if !method.IsStreamingClient() {
+		switch len(messages) {
+		case 0:
+			messages = append(messages, dynamicpb.NewMessage(method.Input()))
+		case 1:
+		default:
+			return nil, fmt.Errorf("--data has %d messages but %s is not client streaming", len(messages), method.Name())
+		}
+	}
+	return messages, nil
+}
+
+func encodeGRPCMessages(messages []proto.Message, resolver protoschema.Resolver) [][]byte {
+	encoded := make([][]byte, len(messages))
+	for i, message := range messages {
+		encoded[i] = encodeGRPCMessage(message, resolver)
+	}
+	return encoded
+}
+
+// encodeGRPCMessage renders a message as compact JSON; protojson adds
+// random whitespace on purpose, so the result is compacted again.
+func encodeGRPCMessage(message proto.Message, resolver protoschema.Resolver) []byte {
+	data, err := protojson.MarshalOptions{Resolver: resolver}.Marshal(message)
+	if err != nil {
+		return []byte(fmt.Sprintf("%q", err.Error()))
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
+
+func newGRPCCall(opts structs.RequestOptions, method protoreflect.MethodDescriptor) *structs.GRPCCall {
+	return &structs.GRPCCall{
+		Method:   protoschema.Method(method),
+		Path:     protoschema.MethodPath(method),
+		Metadata: grpcMetadata(opts),
+		ShowTLS:  opts.TLSInfo,
+		Output:   opts.Output,
+	}
+}
+
+// grpcMetadata sends --header values and the auth flags as request metadata,
+// whose keys are lowercase.
+func grpcMetadata(opts structs.RequestOptions) map[string][]string {
+	md := map[string][]string{}
+	for name, values := range opts.Headers {
+		key := strings.ToLower(name)
+		md[key] = append(md[key], values...)
+	}
+	if authHeader := authorizationHeader(opts.Bearer, opts.Basic); authHeader != "" {
+		md["authorization"] = []string{authHeader}
+	}
+	return md
+}
+
+// textMetadata base64-encodes the values of binary (-bin) keys, which gRPC
+// hands over decoded, as they travel on the wire.
+func textMetadata(md metadata.MD) map[string][]string {
+	text := make(map[string][]string, len(md))
+	for name, values := range md {
+		if !strings.HasSuffix(name, "-bin") {
+			text[name] = values
+			continue
+		}
+		for _, value := range values {
+			text[name] = append(text[name], base64.RawStdEncoding.EncodeToString([]byte(value)))
+		}
+	}
+	return text
+}
+
+// invokeGRPC runs any kind of call over a generic stream: the requests are
+// sent, then every response is read until the server ends the call. The
+// error is the final status of the call, nil when it is OK.
+func invokeGRPC(ctx context.Context, conn *grpc.ClientConn, method protoreflect.MethodDescriptor, requests []proto.Message, call *structs.GRPCCall, timing *structs.TimingInfo, resolver protoschema.Resolver) error {
+	desc := &grpc.StreamDesc{
+		StreamName:    string(method.Name()),
+		ClientStreams: method.IsStreamingClient(),
+		ServerStreams: method.IsStreamingServer(),
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.MD(call.Metadata))
+	timing.RequestStart = time.Now()
+
+	stream, err := conn.NewStream(ctx, desc, call.Path)
+	if err != nil {
+		return finishGRPCCall(call, err, timing)
+	}
+
+	for _, request := range requests {
+		if err := stream.SendMsg(request); err != nil {
+			// io.EOF means the server already ended the call; its status
+			// is read below.
+			if !errors.Is(err, io.EOF) {
+				return finishGRPCCall(call, err, timing)
+			}
+			break
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return finishGRPCCall(call, err, timing)
+	}
+	timing.RequestDone = time.Now()
+
+	if header, err := stream.Header(); err == nil {
+		timing.ResponseStart = time.Now()
+		call.Headers = textMetadata(header)
+	}
+
+	for {
+		response := dynamicpb.NewMessage(method.Output())
+		if err = stream.RecvMsg(response); err != nil {
+			break
+		}
+		call.Responses = append(call.Responses, encodeGRPCMessage(response, resolver))
+		if call.Live {
+			ui.DisplayGRPCMessage(*call, call.Responses[len(call.Responses)-1], time.Since(timing.RequestStart))
+		}
+	}
+	call.Trailers = textMetadata(stream.Trailer())
+
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return finishGRPCCall(call, err, timing)
+}
+
+// finishGRPCCall records the final status and returns err unchanged.
+func finishGRPCCall(call *structs.GRPCCall, err error, timing *structs.TimingInfo) error {
+	timing.ResponseDone = time.Now()
+	if timing.ResponseStart.IsZero() && len(call.Headers) > 0 {
+		timing.ResponseStart = timing.ResponseDone
+	}
+	call.Timing = timing
+	setGRPCStatus(call, status.Convert(err))
+	return err
+}
+
+func setGRPCStatus(call *structs.GRPCCall, st *status.Status) {
+	call.Code, call.CodeName, call.Message = int(st.Code()), st.Code().String(), st.Message()
+	for _, detail := range st.Proto().GetDetails() {
+		data, err := protojson.Marshal(detail)
+		if err != nil {
+			data = []byte(fmt.Sprintf(`{"@type":%q}`, detail.GetTypeUrl()))
+		}
+		call.Details = append(call.Details, data)
+	}
+}
+
+// displayGRPCCall shows the call and applies --fail: any status other than
+// OK fails, like an HTTP error status does.
+func displayGRPCCall(call *structs.GRPCCall, opts structs.RequestOptions) error {
+	if opts.Expect.Fail {
+		call.Assertions = []structs.Assertion{{
+			Name:   "status OK (--fail)",
+			Passed: codes.Code(call.Code) == codes.OK,
+			Actual: call.CodeName,
+		}}
+	}
+
+	if err := ui.DisplayGRPC(*call); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	if failed := failedAssertions(call.Assertions); failed > 0 {
+		return &AssertionError{Failed: failed, Total: len(call.Assertions)}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/protoschema"
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	grpcWebContentType = "application/grpc-web+proto"
+	// grpcWebCompressedFlag marks a compressed frame; charm never offers
+	// grpc-accept-encoding, so servers should not send one.
+	grpcWebCompressedFlag = 0x01
+	// grpcWebTrailerFlag marks the frame that carries the trailers.
+	grpcWebTrailerFlag = 0x80
+)
+
+// grpcWeb calls a method through a gRPC-Web proxy or server (e.g. Envoy),
+// which speaks HTTP/1.1 and so goes through the regular HTTP client, proxy
+// settings included. Reflection needs HTTP/2 streams, hence --proto.
+func grpcWeb(opts structs.RequestOptions, grpcOpts structs.GRPCOptions) error {
+	parsedURL, err := validateURL(resolveURL(opts.BaseURL, opts.URL))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if len(grpcOpts.ProtoFiles) == 0 {
+		return fmt.Errorf("--web needs --proto: server reflection is not available over gRPC-Web")
+	}
+
+	ctx, cancel := newSignalContext()
+	defer cancel()
+
+	callCtx, cancelCall := newAttemptContext(ctx, opts)
+	defer cancelCall()
+
+	schema, err := protoschema.FromProtoFiles(callCtx, grpcOpts.ProtoFiles, grpcOpts.ImportPaths)
+	if err != nil {
+		return err
+	}
+
+	if grpcOpts.List {
+		return ui.DisplayGRPCServices(schema.Services(), opts.Output)
+	}
+
+	method, err := schema.FindMethod(grpcOpts.Method)
+	if err != nil {
+		return err
+	}
+	if method.IsStreamingClient() {
+		return fmt.Errorf("%s is client streaming, which gRPC-Web does not support", method.Name())
+	}
+
+	requests, err := decodeGRPCMessages(opts.Data, method, schema.Resolver())
+	if err != nil {
+		return err
+	}
+
+	call := newGRPCCall(opts, method)
+	call.Web, call.Secure = true, parsedURL.Scheme == "https"
+	parsedURL.Path = strings.TrimRight(parsedURL.Path, "/") + call.Path
+	call.Target = parsedURL.String()
+	call.Requests = encodeGRPCMessages(requests, schema.Resolver())
+	ui.DisplayGRPCStart(*call)
+
+	client, err := newHTTPClient(opts)
+	if err != nil {
+		return fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	timing := &structs.TimingInfo{RequestStart: time.Now()}
+	proxyURL, _ := proxyFor(opts, parsedURL)
+	traceCtx := httptrace.WithClientTrace(callCtx, createClientTrace(timing, proxyURL != nil && usesConnectTunnel(proxyURL, parsedURL)))
+
+	err = invokeGRPCWeb(traceCtx, client, method, requests[0], call, timing, schema.Resolver())
+	call.TotalTime = time.Since(timing.RequestStart)
+	if err != nil {
+		display := structs.NewDisplay(http.MethodPost, call.Target).
+			WithTiming(call.TotalTime, timing).
+			WithTLSInfo(opts.TLSInfo).
+			WithOutput(opts.Output)
+		return &TransportError{Err: displayFailure(display, newFailure(callCtx, err, timing, opts))}
+	}
+
+	return displayGRPCCall(call, opts)
+}
+
+// invokeGRPCWeb sends the request as a single length-prefixed frame and reads
+// the response frames; the status comes in a trailer frame, or in the
+// headers when the server answers without any message.
+func invokeGRPCWeb(ctx context.Context, client *http.Client, method protoreflect.MethodDescriptor, request proto.Message, call *structs.GRPCCall, timing *structs.TimingInfo, resolver protoschema.Resolver) error {
+	payload, err := proto.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(payload)))
+	body = append(body, payload...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, call.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range call.Metadata {
+		req.Header[textproto.CanonicalMIMEHeaderKey(name)] = values
+	}
+	req.Header.Set("Content-Type", grpcWebContentType)
+	req.Header.Set("Accept", grpcWebContentType)
+	req.Header.Set("X-Grpc-Web", "1")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	timing.TLS = resp.TLS
+	call.Headers = lowercaseHeader(resp.Header)
+
+	data, err := io.ReadAll(resp.Body)
+	timing.ResponseDone = time.Now()
+	call.Timing = timing
+	if err != nil {
+		return err
+	}
+
+	// A proxy error page (404, 503...) is not framed: map its HTTP status.
+	if resp.StatusCode != http.StatusOK && len(call.Headers["grpc-status"]) == 0 {
+		setGRPCStatus(call, status.New(httpStatusCode(resp.StatusCode), resp.Status))
+		return nil
+	}
+
+	trailers, err := readGRPCWebFrames(data, method, call, resolver)
+	if err != nil {
+		setGRPCStatus(call, status.New(codes.Internal, err.Error()))
+		return nil
+	}
+
+	call.Trailers = trailers
+
+	// A trailers-only response puts the status in the HTTP headers.
+	statusSource := trailers
+	if statusSource == nil {
+		statusSource = call.Headers
+	}
+
+	if len(statusSource["grpc-status"]) > 0 {
+		setGRPCStatus(call, grpcWebStatus(statusSource))
+	} else {
+		setGRPCStatus(call, status.New(codes.Internal, "response ended without a grpc-status trailer"))
+	}
+	return nil
+}
+
+func readGRPCWebFrames(data []byte, method protoreflect.MethodDescriptor, call *structs.GRPCCall, resolver protoschema.Resolver) (map[string][]string, error) {
+	var trailers map[string][]string
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, fmt.Errorf("truncated gRPC-Web frame")
+		}
+		flags, size := data[0], binary.BigEndian.Uint32(data[1:5])
+		if uint64(len(data)-5) < uint64(size) {
+			return nil, fmt.Errorf("truncated gRPC-Web frame: want %d bytes, have %d", size, len(data)-5)
+		}
+		payload := data[5 : 5+size]
+		data = data[5+size:]
+
+		if flags&grpcWebCompressedFlag != 0 {
+			return nil, fmt.Errorf("compressed gRPC-Web frames are not supported")
+		}
+
+		if flags&grpcWebTrailerFlag != 0 {
+			trailers = parseGRPCWebTrailers(payload)
+			continue
+		}
+
+		response := dynamicpb.NewMessage(method.Output())
+		if err := (proto.UnmarshalOptions{Resolver: resolver}).Unmarshal(payload, response); err != nil {
+			return nil, fmt.Errorf("invalid %s message: %w", method.Output().FullName(), err)
+		}
+		call.Responses = append(call.Responses, encodeGRPCMessage(response, resolver))
+	}
+	return trailers, nil
+}
+
+// parseGRPCWebTrailers reads the "name: value\r\n" lines of a trailer frame.
+func parseGRPCWebTrailers(payload []byte) map[string][]string {
+	trailers := map[string][]string{}
+	for _, line := range strings.Split(string(payload), "\r\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		trailers[name] = append(trailers[name], strings.TrimSpace(value))
+	}
+	return trailers
+}
+
+func grpcWebStatus(trailers map[string][]string) *status.Status {
+	code, err := strconv.Atoi(trailers["grpc-status"][0])
+	if err != nil {
+		return status.New(codes.Unknown, "invalid grpc-status "+trailers["grpc-status"][0])
+	}
+
+	message := ""
+	if values := trailers["grpc-message"]; len(values) > 0 {
+		// grpc-message is percent-encoded.
+		message, err = url.PathUnescape(values[0])
+		if err != nil {
+			message = values[0]
+		}
+	}
+
+	// Rich error details travel as a base64 google.rpc.Status.
+	if values := trailers["grpc-status-details-bin"]; len(values) > 0 {
+		data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(values[0], "="))
+		details := &spb.Status{}
+		if err == nil && proto.Unmarshal(data, details) == nil && details.GetCode() == int32(code) {
+			return status.FromProto(details)
+		}
+	}
+	return status.New(codes.Code(code), message)
+}
+
+// httpStatusCode maps HTTP errors from proxies to gRPC codes, as specified
+// in the gRPC HTTP mapping document.
+func httpStatusCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
+
+func lowercaseHeader(header http.Header) map[string][]string {
+	lowered := make(map[string][]string, len(header))
+	for name, values := range header {
+		lowered[strings.ToLower(name)] = values
+	}
+	return lowered
+}
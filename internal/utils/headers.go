@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ParseHeaders(values []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, value := range values {
+		name, headerValue, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: expected format 'Name: Value'", value)
+		}
+		headers.Add(name, strings.TrimSpace(headerValue))
+	}
+	return headers, nil
+}
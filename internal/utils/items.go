@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	itemRawJSONSeparator = ":="
+	itemQuerySeparator   = "=="
+	itemFieldSeparator   = "="
+	itemHeaderSeparator  = ":"
+)
+
+var itemSeparators = []string{itemRawJSONSeparator, itemQuerySeparator, itemFieldSeparator, itemHeaderSeparator}
+
+type RequestItems struct {
+	Headers http.Header
+	Query   url.Values
+	Body    map[string]any
+}
+
+func ParseRequestItems(items []string) (*RequestItems, error) {
+	parsed := &RequestItems{
+		Headers: http.Header{},
+		Query:   url.Values{},
+	}
+
+	for _, item := range items {
+		key, separator, value := splitItem(item)
+		if separator == "" || key == "" {
+			return nil, fmt.Errorf("invalid request item %q: expected name=value, name:=json, name==query or Header:value", item)
+		}
+
+		switch separator {
+		case itemHeaderSeparator:
+			parsed.Headers.Add(key, value)
+		case itemQuerySeparator:
+			parsed.Query.Add(key, value)
+		case itemFieldSeparator:
+			if err := parsed.setField(key, value); err != nil {
+				return nil, fmt.Errorf("invalid request item %q: %w", item, err)
+			}
+		case itemRawJSONSeparator:
+			raw, err := decodeRawJSON(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON in request item %q: %w", item, err)
+			}
+			if err := parsed.setField(key, raw); err != nil {
+				return nil, fmt.Errorf("invalid request item %q: %w", item, err)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+// decodeRawJSON decodes a single JSON value, keeping numbers as written and
+// rejecting anything after it ('30 40', '[1] x').
+func decodeRawJSON(value string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var raw any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return raw, nil
+}
+
+func (r *RequestItems) HasBody() bool {
+	return len(r.Body) > 0
+}
+
+func (r *RequestItems) JSONBody() (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r.Body); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func (r *RequestItems) setField(key string, value any) error {
+	path, err := parseFieldPath(key)
+	if err != nil {
+		return err
+	}
+
+	if r.Body == nil {
+		r.Body = map[string]any{}
+	}
+
+	return setNested(r.Body, path, value)
+}
+
+func splitItem(item string) (string, string, string) {
+	for i := 0; i < len(item); i++ {
+		for _, separator := range itemSeparators {
+			if strings.HasPrefix(item[i:], separator) {
+				return item[:i], separator, item[i+len(separator):]
+			}
+		}
+	}
+	return item, "", ""
+}
+
+// parseFieldPath turns "user[address][city]" into ["user", "address", "city"].
+// An empty segment ("tags[]") means "append to array".
+func parseFieldPath(key string) ([]string, error) {
+	open := strings.Index(key, "[")
+	if open == -1 {
+		return []string{key}, nil
+	}
+	if open == 0 {
+		return nil, fmt.Errorf("field name cannot start with '['")
+	}
+
+	path := []string{key[:open]}
+	rest := key[open:]
+	for rest != "" {
+		if rest[0] != '[' {
+			return nil, fmt.Errorf("unexpected %q in field name", rest)
+		}
+		end := strings.Index(rest, "]")
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed '[' in field name")
+		}
+		path = append(path, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	return path, nil
+}
+
+func setNested(target map[string]any, path []string, value any) error {
+	key := path[0]
+
+	if len(path) == 1 {
+		if _, exists := target[key]; exists {
+			return fmt.Errorf("field %q is already set", key)
+		}
+		target[key] = value
+		return nil
+	}
+
+	if path[1] == "" {
+		if len(path) > 2 {
+			return fmt.Errorf("'[]' is only supported at the end of a field name")
+		}
+		existing, exists := target[key]
+		if !exists {
+			target[key] = []any{value}
+			return nil
+		}
+		list, ok := existing.([]any)
+		if !ok {
+			return fmt.Errorf("field %q is not an array", key)
+		}
+		target[key] = append(list, value)
+		return nil
+	}
+
+	existing, exists := target[key]
+	if !exists {
+		existing = map[string]any{}
+		target[key] = existing
+	}
+
+	nested, ok := existing.(map[string]any)
+	if !ok {
+		return fmt.Errorf("field %q is not an object", key)
+	}
+
+	return setNested(nested, path[1:], value)
+}
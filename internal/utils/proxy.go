@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+var supportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+type timingContextKey struct{}
+
+// ParseProxyURL validates --proxy and merges --proxy-user into its credentials.
+func ParseProxyURL(rawProxy, proxyUser string) (*url.URL, error) {
+	if rawProxy == "" {
+		if proxyUser != "" {
+			return nil, fmt.Errorf("--proxy-user requires --proxy")
+		}
+		return nil, nil
+	}
+
+	if !strings.Contains(rawProxy, "://") {
+		rawProxy = "http://" + rawProxy
+	}
+
+	proxyURL, err := url.Parse(rawProxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --proxy: %w", err)
+	}
+
+	if !isSupportedProxyScheme(proxyURL.Scheme) {
+		return nil, fmt.Errorf("invalid --proxy scheme %q: must be one of %s", proxyURL.Scheme, strings.Join(supportedProxySchemes, ", "))
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid --proxy: missing host")
+	}
+
+	if proxyUser != "" {
+		username, password, _ := strings.Cut(proxyUser, ":")
+		proxyURL.User = url.UserPassword(username, password)
+	}
+
+	return proxyURL, nil
+}
+
+func ParseNoProxy(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func isSupportedProxyScheme(scheme string) bool {
+	for _, supported := range supportedProxySchemes {
+		if scheme == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyFor picks the proxy for a target URL: --noproxy wins, then --proxy,
+// then the standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables.
+func proxyFor(opts structs.RequestOptions, target *url.URL) (*url.URL, error) {
+	if matchesNoProxy(opts.NoProxy, target.Hostname()) {
+		return nil, nil
+	}
+
+	if opts.Proxy != nil {
+		return opts.Proxy, nil
+	}
+
+	return http.ProxyFromEnvironment(&http.Request{URL: target})
+}
+
+func matchesNoProxy(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		if pattern == "*" {
+			return true
+		}
+
+		if _, network, err := net.ParseCIDR(pattern); err == nil {
+			if ip := net.ParseIP(host); ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		pattern = strings.TrimPrefix(pattern, ".")
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesConnectTunnel reports whether the request goes through an HTTP proxy
+// with a CONNECT tunnel (HTTPS targets behind http:// or https:// proxies).
+func usesConnectTunnel(proxyURL, target *url.URL) bool {
+	return proxyURL != nil && target.Scheme == "https" &&
+		(proxyURL.Scheme == "http" || proxyURL.Scheme == "https")
+}
+
+func onProxyConnectResponse(ctx context.Context, _ *url.URL, _ *http.Request, _ *http.Response) error {
+	if timing, ok := ctx.Value(timingContextKey{}).(*structs.TimingInfo); ok {
+		timing.ProxyConnectDone = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+func ParseQueryParams(values []string) (url.Values, error) {
+	params := url.Values{}
+	for _, value := range values {
+		key, paramValue, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid query parameter %q: expected format 'key=value'", value)
+		}
+		params.Add(key, paramValue)
+	}
+	return params, nil
+}
+
+func mergeQuery(parsedURL *url.URL, params url.Values) {
+	if len(params) == 0 {
+		return
+	}
+
+	if parsedURL.RawQuery == "" {
+		parsedURL.RawQuery = params.Encode()
+		return
+	}
+
+	parsedURL.RawQuery += "&" + params.Encode()
+}
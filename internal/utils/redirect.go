@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+const DefaultMaxRedirects = 10
+
+func isRedirect(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return resp.Header.Get("Location") != ""
+	}
+	return false
+}
+
+// redirectOptions builds the options for the next hop following the same rules
+// as browsers: 301/302/303 switch to GET without a body (unless --keep-method
+// is set for 301/302) and credentials are dropped when the host changes
+// (unless --keep-auth is set).
+func redirectOptions(current structs.RequestOptions, resp *http.Response, opts structs.RequestOptions) (structs.RequestOptions, string, error) {
+	location, err := resp.Location()
+	if err != nil {
+		return current, "", fmt.Errorf("invalid Location header %q: %w", resp.Header.Get("Location"), err)
+	}
+
+	next := current
+	next.URL = location.String()
+	next.Query = nil
+	next.Headers = current.Headers.Clone()
+
+	if switchesToGet(resp.StatusCode, current.Method, opts.KeepMethod) {
+		next.Method = http.MethodGet
+		next.Data, next.DataFile, next.Form = "", "", nil
+		next.ContentType = ""
+		next.Headers.Del("Content-Type")
+	} else if next.DataFile == StdinDataFile {
+		return current, "", fmt.Errorf("cannot replay a body read from stdin after a %d redirect", resp.StatusCode)
+	}
+
+	if !strings.EqualFold(resp.Request.URL.Host, location.Host) {
+		next.Headers.Del("Host")
+		if !opts.KeepAuth {
+			next.Bearer, next.Basic = "", ""
+			next.Headers.Del("Authorization")
+			next.Headers.Del("Cookie")
+		}
+	}
+
+	return next, location.String(), nil
+}
+
+func switchesToGet(statusCode int, method string, keepMethod bool) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return false
+	}
+
+	switch statusCode {
+	case http.StatusSeeOther:
+		return true
+	case http.StatusMovedPermanently, http.StatusFound:
+		return !keepMethod
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+)
+
+const (
+	RetryOnNetwork    = "network"
+	DefaultRetryOn    = "429,5xx,network"
+	DefaultRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
+)
+
+// ParseRetryOn validates a --retry-on list such as "429,5xx,network".
+func ParseRetryOn(value string) ([]string, error) {
+	var conditions []string
+	for _, condition := range strings.Split(value, ",") {
+		condition = strings.ToLower(strings.TrimSpace(condition))
+		if condition == "" {
+			continue
+		}
+		if !isValidRetryCondition(condition) {
+			return nil, fmt.Errorf("invalid --retry-on condition %q: expected a status code (503), a class (5xx) or 'network'", condition)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func isValidRetryCondition(condition string) bool {
+	return condition == RetryOnNetwork || isStatusCondition(condition)
+}
+
+// isStatusCondition accepts a status code (503) or a class (5xx).
+func isStatusCondition(condition string) bool {
+	if len(condition) != 3 || condition[0] < '1' || condition[0] > '5' {
+		return false
+	}
+	if condition[1:] == "xx" {
+		return true
+	}
+	_, err := strconv.Atoi(condition)
+	return err == nil
+}
+
+// shouldRetry never replays interruptions or redirect failures: a redirect
+// loop or a bad Location header fails the same way every time.
+func shouldRetry(conditions []string, statusCode int, failure *structs.Failure) bool {
+	for _, condition := range conditions {
+		if failure != nil {
+			if condition == RetryOnNetwork && failure.Kind != structs.FailureInterrupted && failure.Phase != structs.PhaseRedirect {
+				return true
+			}
+			continue
+		}
+
+		if matchesStatus(condition, statusCode) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesStatus(condition string, statusCode int) bool {
+	code := strconv.Itoa(statusCode)
+	return condition == code || (strings.HasSuffix(condition, "xx") && condition[0] == code[0])
+}
+
+// retryDelay returns how long to wait before the next attempt, preferring the
+// server's Retry-After header over exponential backoff with jitter. Both are
+// capped at maxRetryDelay so a server cannot park charm for hours.
+func retryDelay(resp *http.Response, attempt int, base time.Duration) (time.Duration, bool) {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(delay, maxRetryDelay), true
+		}
+	}
+
+	if base <= 0 {
+		base = DefaultRetryDelay
+	}
+
+	backoff := base << (attempt - 1)
+	if backoff <= 0 || backoff > maxRetryDelay {
+		backoff = maxRetryDelay
+	}
+
+	half := backoff / 2
+	return half + rand.N(half+1), false
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(0, time.Until(date)), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+)
+
+const (
+	LastEventIDHeader = "Last-Event-ID"
+	defaultSSERetry   = 3 * time.Second
+)
+
+var ndjsonMediaTypes = map[string]bool{
+	"application/x-ndjson":    true,
+	"application/ndjson":      true,
+	"application/jsonl":       true,
+	"application/x-jsonlines": true,
+}
+
+// streamMode tells whether a response is rendered while it arrives: SSE and
+// NDJSON are detected from the Content-Type, --stream forces line mode. A
+// streamed body is not kept, so responses that --expect-json or a caller
+// reads afterwards are never streamed.
+func streamMode(opts structs.RequestOptions, resp *http.Response) string {
+	if isDownload(opts) || opts.KeepBody || len(opts.Expect.JSON) > 0 {
+		return ""
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "text/event-stream":
+		return structs.StreamSSE
+	case ndjsonMediaTypes[mediaType]:
+		return structs.StreamNDJSON
+	case opts.Stream:
+		return structs.StreamLines
+	}
+	return ""
+}
+
+// streamResponseBody renders records as they arrive. With --reconnect, a
+// dropped SSE connection is reopened with Last-Event-ID until the server
+// answers 204 or the user interrupts. Interruptions and --max-time end the
+// stream normally rather than as a failure.
+func streamResponseBody(ctx context.Context, client *http.Client, opts structs.RequestOptions, resp *http.Response, mode string) (*structs.StreamSummary, error) {
+	streamURL := resp.Request.URL.String()
+	ui.DisplayStreamStart(opts.Method, streamURL, resp, mode, opts.Output)
+
+	reader := &streamReader{
+		mode:    mode,
+		output:  opts.Output,
+		start:   time.Now(),
+		lastID:  opts.Headers.Get(LastEventIDHeader),
+		retry:   defaultSSERetry,
+		summary: &structs.StreamSummary{Mode: mode},
+	}
+
+	for {
+		err := reader.read(resp.Body)
+		resp.Body.Close()
+
+		if ctx.Err() != nil {
+			reader.summary.StopReason = stopReason(ctx)
+			return reader.summary, nil
+		}
+		if mode != structs.StreamSSE || !opts.Reconnect {
+			return reader.summary, err
+		}
+
+		for {
+			ui.DisplayStreamReconnect(reader.lastID, reader.retry, err)
+			if !sleepContext(ctx, reader.retry) {
+				reader.summary.StopReason = stopReason(ctx)
+				return reader.summary, nil
+			}
+
+			resp, err = reconnect(ctx, client, opts, streamURL, reader.lastID)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				reader.summary.StopReason = stopReason(ctx)
+				return reader.summary, nil
+			}
+		}
+
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			reader.summary.StopReason = "server ended the stream (204)"
+			return reader.summary, nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return reader.summary, fmt.Errorf("reconnect failed: %s", resp.Status)
+		}
+
+		reader.summary.Reconnects++
+	}
+}
+
+// reconnect reopens the stream at the URL that served it, following
+// redirects with the same rules as the first request.
+func reconnect(ctx context.Context, client *http.Client, opts structs.RequestOptions, streamURL, lastID string) (*http.Response, error) {
+	hopOpts := opts
+	hopOpts.URL, hopOpts.Query = streamURL, nil
+	hopOpts.Headers = opts.Headers.Clone()
+	if lastID != "" {
+		hopOpts.Headers.Set(LastEventIDHeader, lastID)
+	}
+
+	for redirects := 0; ; redirects++ {
+		req, reqBody, _, err := prepareHop(ctx, hopOpts, &structs.TimingInfo{})
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		reqBody.close()
+		if err != nil || opts.NoFollow || !isRedirect(resp) {
+			return resp, err
+		}
+		resp.Body.Close()
+
+		if redirects >= opts.MaxRedirects {
+			return nil, fmt.Errorf("stopped after %d redirects (use --max-redirects to raise the limit)", opts.MaxRedirects)
+		}
+		if hopOpts, _, err = redirectOptions(hopOpts, resp, opts); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func stopReason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "stopped by --max-time"
+	}
+	return "interrupted"
+}
+
+type streamReader struct {
+	mode    string
+	output  string
+	start   time.Time
+	lastID  string
+	retry   time.Duration
+	summary *structs.StreamSummary
+
+	event string
+	id    string
+	data  []string
+}
+
+// read consumes one connection line by line; it returns nil at EOF.
+func (r *streamReader) read(body io.Reader) error {
+	reader := bufio.NewReader(body)
+
+	for {
+		line, err := reader.ReadString('\n')
+		r.summary.Bytes += int64(len(line))
+
+		if r.output == structs.OutputRaw {
+			os.Stdout.WriteString(line)
+		}
+		if line != "" {
+			r.handleLine(strings.TrimRight(line, "\r\n"))
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *streamReader) handleLine(line string) {
+	switch r.mode {
+	case structs.StreamNDJSON:
+		if strings.TrimSpace(line) != "" {
+			r.emit(structs.StreamEvent{Data: line})
+		}
+	case structs.StreamLines:
+		r.emit(structs.StreamEvent{Data: line})
+	case structs.StreamSSE:
+		r.handleSSELine(line)
+	}
+}
+
+// handleSSELine follows the event-stream format: "field: value" lines build
+// an event that a blank line dispatches; lines starting with ":" are comments.
+func (r *streamReader) handleSSELine(line string) {
+	if line == "" {
+		if len(r.data) > 0 {
+			event := r.event
+			if event == "" {
+				event = "message"
+			}
+			r.emit(structs.StreamEvent{Event: event, ID: r.id, Data: strings.Join(r.data, "\n")})
+		}
+		r.event, r.id, r.data = "", "", nil
+		return
+	}
+
+	if strings.HasPrefix(line, ":") {
+		return
+	}
+
+	field, value, _ := strings.Cut(line, ":")
+	value = strings.TrimPrefix(value, " ")
+
+	switch field {
+	case "event":
+		r.event = value
+	case "data":
+		r.data = append(r.data, value)
+	case "id":
+		if !strings.ContainsRune(value, 0) {
+			r.id, r.lastID = value, value
+		}
+	case "retry":
+		if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+			r.retry = time.Duration(ms) * time.Millisecond
+		}
+	}
+}
+
+func (r *streamReader) emit(event structs.StreamEvent) {
+	event.Mode = r.mode
+	event.Elapsed = time.Since(r.start)
+	r.summary.Events++
+
+	if r.output != structs.OutputRaw {
+		ui.DisplayStreamEvent(event, r.output)
+	}
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const pinPrefix = "sha256//"
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var errPinMismatch = errors.New("public key pin mismatch")
+
+func ParseTLSVersion(flag, version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+
+	value, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("invalid %s %q: must be one of 1.0, 1.1, 1.2, 1.3", flag, version)
+	}
+	return value, nil
+}
+
+func ParsePins(pins []string) ([][]byte, error) {
+	parsed := make([][]byte, 0, len(pins))
+	for _, pin := range pins {
+		for _, value := range strings.Split(pin, ";") {
+			value = strings.TrimPrefix(strings.TrimSpace(value), pinPrefix)
+			if value == "" {
+				continue
+			}
+			hash, err := base64.StdEncoding.DecodeString(value)
+			if err != nil || len(hash) != sha256.Size {
+				return nil, fmt.Errorf("invalid --pin-sha256 %q: expected a base64 SHA-256 of the public key", value)
+			}
+			parsed = append(parsed, hash)
+		}
+	}
+	return parsed, nil
+}
+
+func newTLSConfig(opts structs.TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: opts.Insecure,
+		MinVersion:         opts.MinVersion,
+		MaxVersion:         opts.MaxVersion,
+		ServerName:         opts.ServerName,
+	}
+
+	if opts.MinVersion != 0 && opts.MaxVersion != 0 && opts.MinVersion > opts.MaxVersion {
+		return nil, fmt.Errorf("--tls-min cannot be greater than --tls-max")
+	}
+
+	if opts.CACert != "" {
+		pool, err := loadCACerts(opts.CACert)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.Cert != "" {
+		certificate, err := loadClientCertificate(opts)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	} else if opts.Key != "" {
+		return nil, fmt.Errorf("--key requires --cert")
+	}
+
+	if len(opts.Pins) > 0 {
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyPins(state, opts.Pins)
+		}
+	}
+
+	return config, nil
+}
+
+func loadCACerts(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --cacert: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("--cacert %s does not contain any PEM certificate", path)
+	}
+	return pool, nil
+}
+
+func loadClientCertificate(opts structs.TLSOptions) (tls.Certificate, error) {
+	if isPKCS12(opts.Cert) {
+		return loadPKCS12(opts.Cert, opts.CertPassword)
+	}
+
+	keyPath := opts.Key
+	if keyPath == "" {
+		// A single PEM file may hold both the certificate and the key.
+		keyPath = opts.Cert
+	}
+
+	certificate, err := tls.LoadX509KeyPair(opts.Cert, keyPath)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return certificate, nil
+}
+
+func isPKCS12(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".p12" || ext == ".pfx"
+}
+
+func loadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to decode PKCS#12 certificate (wrong --cert-password?): %w", err)
+	}
+
+	certificate := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for _, cert := range chain {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+	return certificate, nil
+}
+
+// verifyPins checks the leaf key only: anything further up the chain is
+// whatever the server chose to send, so a pinned certificate appended to a
+// foreign chain must not pass.
+func verifyPins(state tls.ConnectionState, pins [][]byte) error {
+	if len(state.PeerCertificates) == 0 {
+		return errPinMismatch
+	}
+
+	leaf := sha256.Sum256(state.PeerCertificates[0].RawSubjectPublicKeyInfo)
+	for _, pin := range pins {
+		if bytes.Equal(leaf[:], pin) {
+			return nil
+		}
+	}
+	return &tls.CertificateVerificationError{
+		UnverifiedCertificates: state.PeerCertificates,
+		Err:                    fmt.Errorf("%w: server key is %s%s", errPinMismatch, pinPrefix, base64.StdEncoding.EncodeToString(leaf[:])),
+	}
+}
+
+// unverifiedState keeps the chain of a rejected certificate so --tls-info can
+// still show it. A failed handshake negotiates nothing, so the rest of the
+// state stays empty and HandshakeComplete false.
+func unverifiedState(err error) *tls.ConnectionState {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil
+	}
+	return &tls.ConnectionState{PeerCertificates: verifyErr.UnverifiedCertificates}
+}
+
+// tlsHint explains common certificate failures and how to address them.
+func tlsHint(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var recordHeader tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return "certificate is signed by an unknown authority: pass the issuing CA with --cacert, or --insecure to skip verification"
+	case errors.As(err, &hostname):
+		return fmt.Sprintf("certificate is not valid for %q: check the URL or use --servername to verify another name", hostname.Host)
+	case errors.As(err, &invalid):
+		switch invalid.Reason {
+		case x509.Expired:
+			return fmt.Sprintf("certificate is expired or not yet valid (valid %s to %s)",
+				invalid.Cert.NotBefore.Format(time.DateOnly), invalid.Cert.NotAfter.Format(time.DateOnly))
+		case x509.NotAuthorizedToSign:
+			return "an intermediate certificate is not allowed to sign other certificates"
+		case x509.IncompatibleUsage:
+			return "certificate is not valid for server authentication"
+		}
+		return "certificate is invalid: " + invalid.Error()
+	case errors.As(err, &recordHeader), strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		return "server did not answer with TLS: is it plain HTTP? try http://"
+	case errors.Is(err, errPinMismatch):
+		return "server public key does not match --pin-sha256"
+	case strings.Contains(err.Error(), "tls: protocol version not supported"):
+		return "no TLS version in common with the server: adjust --tls-min/--tls-max"
+	case strings.Contains(err.Error(), "certificate required"):
+		return "server requires a client certificate: pass --cert and --key"
+	}
+	return ""
+}
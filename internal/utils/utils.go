@@ -1,13 +1,18 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -21,107 +26,398 @@ const (
 	BasicPrefix        = "Basic "
 )
 
+type attemptResult struct {
+	req        *http.Request
+	reqBody    *requestBody
+	resp       *http.Response
+	body       []byte
+	authHeader string
+	timing     *structs.TimingInfo
+	totalTime  time.Duration
+	failure    *structs.Failure
+	redirects  []structs.Redirect
+	proxy      *url.URL
+	saved      *structs.SavedFile
+	stream     *structs.StreamSummary
+}
+
 func DoRequest(opts structs.RequestOptions) (*http.Response, error) {
-	if err := validateURL(opts.URL); err != nil {
+	parsedURL, err := validateURL(resolveURL(opts.BaseURL, opts.URL))
+	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	startTime := time.Now()
-	timing := &structs.TimingInfo{}
+	mergeQuery(parsedURL, opts.Query)
+	opts.URL = parsedURL.String()
 
-	req, err := createRequest(opts, timing)
+	filter, err := CompileFilter(opts.Filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	authHeader := addAuthentication(req, opts.Bearer, opts.Basic)
-	setContentType(req, opts.ContentType, opts.Data)
+	if opts.Retry > 0 && opts.DataFile == StdinDataFile {
+		// stdin can only be read once, so buffer it to replay it on retries.
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body from stdin: %w", err)
+		}
+		opts.Data, opts.DataFile = string(data), ""
+	}
 
-	timing.RequestStart = time.Now()
-	resp, err := http.DefaultClient.Do(req)
-	timing.RequestDone = time.Now()
+	ctx, cancel := newSignalContext()
+	defer cancel()
 
+	client, err := newHTTPClient(opts)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
 	}
 
-	body, err := readResponseBody(resp)
-	timing.ResponseDone = time.Now()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+	var attempts []structs.Attempt
+	var result *attemptResult
+
+	for number := 1; ; number++ {
+		result, err = doAttempt(ctx, client, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		attempt := newAttempt(number, result)
+		if number > opts.Retry || !shouldRetry(opts.RetryOn, attempt.StatusCode, result.failure) {
+			attempts = append(attempts, attempt)
+			break
+		}
 
-	totalTime := timing.ResponseDone.Sub(startTime)
+		attempt.RetryIn, attempt.RetryAfter = retryDelay(result.resp, number, opts.RetryDelay)
+		attempts = append(attempts, attempt)
+		ui.DisplayRetry(attempt, opts.Retry+1)
+
+		if !sleepContext(ctx, attempt.RetryIn) {
+			break
+		}
+	}
 
 	display := structs.NewDisplay(opts.Method, opts.URL).
-		WithAuth(opts.Bearer, opts.Basic, authHeader).
+		WithAuth(opts.Bearer, opts.Basic, result.authHeader).
 		WithContent(opts.ContentType, opts.Data).
-		WithHTTP(req, resp, body).
-		WithTiming(totalTime, timing)
+		WithBodySource(opts.DataFile, opts.Form, result.reqBody.size()).
+		WithHTTP(result.req, result.resp, result.body).
+		WithTiming(result.totalTime, result.timing).
+		WithAttempts(attempts).
+		WithRedirects(result.redirects).
+		WithProxy(result.proxy).
+		WithTLSInfo(opts.TLSInfo).
+		WithSavedFile(result.saved).
+		WithStream(result.stream).
+		WithGraphQL(opts.GraphQL).
+		WithOutput(opts.Output)
+
+	if result.failure != nil {
+		return nil, &TransportError{Err: displayFailure(display, result.failure)}
+	}
+
+	assertions := evaluateExpectations(opts.Expect, result.resp, result.body, result.totalTime)
+	display.WithAssertions(assertions)
+
+	// A body the filter cannot handle (e.g. an HTML error page) is still shown
+	// unfiltered before reporting the error.
+	var filterErr error
+	if filter != nil && result.stream == nil {
+		results, err := filterBody(ctx, filter, result.body)
+		if err == nil {
+			display.WithFilter(opts.Filter, results)
+		}
+		filterErr = err
+	}
+
+	if err := ui.Display(*display); err != nil {
+		return result.resp, fmt.Errorf("failed to write output: %w", err)
+	}
+
+	if failed := failedAssertions(assertions); failed > 0 {
+		return result.resp, &AssertionError{Failed: failed, Total: len(assertions)}
+	}
+
+	return result.resp, filterErr
+}
+
+func doAttempt(parent context.Context, client *http.Client, opts structs.RequestOptions) (*attemptResult, error) {
+	ctx, cancel := newAttemptContext(parent, opts)
+	defer cancel()
+
+	startTime := time.Now()
+	result := &attemptResult{}
+	hopOpts := opts
+
+	for {
+		hopStart := time.Now()
+		if err := doHop(ctx, client, hopOpts, result); err != nil {
+			return nil, err
+		}
+
+		if result.failure != nil || opts.NoFollow || !isRedirect(result.resp) {
+			break
+		}
+
+		if len(result.redirects) >= opts.MaxRedirects {
+			result.failure = &structs.Failure{
+				Kind:  structs.FailureError,
+				Phase: structs.PhaseRedirect,
+				Err:   fmt.Errorf("stopped after %d redirects (use --max-redirects to raise the limit)", opts.MaxRedirects),
+			}
+			break
+		}
+
+		next, location, err := redirectOptions(hopOpts, result.resp, opts)
+		if err != nil {
+			result.failure = &structs.Failure{Kind: structs.FailureError, Phase: structs.PhaseRedirect, Err: err}
+			break
+		}
+
+		result.redirects = append(result.redirects, structs.Redirect{
+			Method:     hopOpts.Method,
+			URL:        hopOpts.URL,
+			StatusCode: result.resp.StatusCode,
+			Location:   location,
+			Duration:   time.Since(hopStart),
+		})
+		hopOpts = next
+	}
+
+	result.totalTime = time.Since(startTime)
+	return result, nil
+}
+
+func doHop(ctx context.Context, client *http.Client, opts structs.RequestOptions, result *attemptResult) error {
+	result.timing = &structs.TimingInfo{}
+
+	req, reqBody, authHeader, err := prepareHop(ctx, opts, result.timing)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	result.req, result.reqBody, result.authHeader = req, reqBody, authHeader
+	result.resp, result.body, result.saved, result.stream = nil, nil, nil, nil
+	result.proxy, _ = proxyFor(opts, req.URL)
+	resumeFrom := setResumeRange(req, opts)
+
+	result.timing.RequestStart = time.Now()
+	resp, err := client.Do(req)
+	if err == nil {
+		result.resp = resp
+		// A reused keep-alive connection (same-host redirect, retry) skips the
+		// handshake the trace records, but the response still carries its state.
+		if result.timing.TLS == nil {
+			result.timing.TLS = resp.TLS
+		}
+		if shouldSave(opts, resp, resumeFrom) {
+			result.saved, err = saveResponseBody(resp, opts, resumeFrom)
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				return fmt.Errorf("failed to save response body: %w", err)
+			}
+		} else if mode := streamMode(opts, resp); mode != "" {
+			result.stream, err = streamResponseBody(ctx, client, opts, resp, mode)
+		} else {
+			result.body, err = readResponseBody(resp)
+		}
+		// Callers such as collections read the body again to capture values.
+		resp.Body = io.NopCloser(bytes.NewReader(result.body))
+		result.timing.ResponseDone = time.Now()
+	}
+
+	if err != nil {
+		result.failure = newFailure(ctx, err, result.timing, opts)
+	}
+
+	return nil
+}
+
+// shouldSave streams only successful responses to disk; error pages and
+// redirects are read into memory and displayed as usual.
+func shouldSave(opts structs.RequestOptions, resp *http.Response, resumeFrom int64) bool {
+	if !isDownload(opts) {
+		return false
+	}
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return resumeFrom > 0
+	}
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func newAttempt(number int, result *attemptResult) structs.Attempt {
+	attempt := structs.Attempt{
+		Number:   number,
+		Duration: result.totalTime,
+	}
+
+	if result.failure != nil {
+		err := result.failure.Err
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		attempt.Error = err.Error()
+	} else {
+		attempt.StatusCode = result.resp.StatusCode
+	}
+
+	return attempt
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
 
-	ui.Display(*display)
+func displayFailure(display *structs.Display, failure *structs.Failure) error {
+	display.WithFailure(failure)
+
+	if displayErr := ui.DisplayFailure(*display); displayErr != nil {
+		return fmt.Errorf("failed to write output: %w", displayErr)
+	}
 
-	return resp, nil
+	switch failure.Kind {
+	case structs.FailureTimeout:
+		return fmt.Errorf("request timed out during %s: %w", failure.Phase, failure.Err)
+	case structs.FailureInterrupted:
+		return fmt.Errorf("request interrupted during %s", failure.Phase)
+	}
+	return fmt.Errorf("request failed during %s: %w", failure.Phase, failure.Err)
 }
 
-func validateURL(rawURL string) error {
+// resolveURL joins relative URLs such as "/users" to the profile base URL,
+// keeping any path prefix of the base ("https://api/v1" + "/users").
+func resolveURL(baseURL, rawURL string) string {
+	if baseURL == "" {
+		return rawURL
+	}
+	// Parsed rather than searched for "://", which a relative URL may carry
+	// in its query ("/callback?next=https://...").
+	if parsed, err := url.Parse(rawURL); err != nil || parsed.IsAbs() {
+		return rawURL
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(rawURL, "/")
+}
+
+func validateURL(rawURL string) (*url.URL, error) {
 	if rawURL == "" {
-		return fmt.Errorf("URL cannot be empty")
+		return nil, fmt.Errorf("URL cannot be empty")
 	}
 
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if parsedURL.Scheme == "" {
-		return fmt.Errorf("URL must have a scheme (http:// or https://)")
+		return nil, fmt.Errorf("URL must have a scheme (http:// or https://)")
 	}
 
 	if parsedURL.Host == "" {
-		return fmt.Errorf("URL must have a host")
+		return nil, fmt.Errorf("URL must have a host")
 	}
 
-	return nil
+	return parsedURL, nil
 }
 
-func createRequest(opts structs.RequestOptions, timing *structs.TimingInfo) (*http.Request, error) {
+// prepareHop builds the request of one hop with its authentication and
+// content type; it returns the Authorization value for display.
+func prepareHop(ctx context.Context, opts structs.RequestOptions, timing *structs.TimingInfo) (*http.Request, *requestBody, string, error) {
+	req, reqBody, err := createRequest(ctx, opts, timing)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	authHeader := addAuthentication(req, opts.Bearer, opts.Basic)
+	setContentType(req, opts, reqBody)
+	return req, reqBody, authHeader, nil
+}
+
+func createRequest(ctx context.Context, opts structs.RequestOptions, timing *structs.TimingInfo) (*http.Request, *requestBody, error) {
+	body, err := newRequestBody(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build request body: %w", err)
+	}
+
 	var bodyReader io.Reader
-	if opts.Data != "" {
-		bodyReader = strings.NewReader(opts.Data)
+	if body != nil {
+		bodyReader = body.reader
 	}
 
-	req, err := http.NewRequest(opts.Method, opts.URL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, opts.Method, opts.URL, bodyReader)
 	if err != nil {
-		return nil, err
+		body.close()
+		return nil, nil, err
+	}
+
+	if body != nil && body.counter != nil && body.contentLength >= 0 {
+		req.ContentLength = body.contentLength
 	}
 
-	trace := createClientTrace(timing)
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	for name, values := range opts.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
 
-	return req, nil
+	if host := opts.Headers.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	proxyURL, err := proxyFor(opts, req.URL)
+	if err != nil {
+		body.close()
+		return nil, nil, fmt.Errorf("failed to resolve proxy: %w", err)
+	}
+	trace := createClientTrace(timing, usesConnectTunnel(proxyURL, req.URL))
+	traceCtx := context.WithValue(req.Context(), timingContextKey{}, timing)
+	req = req.WithContext(httptrace.WithClientTrace(traceCtx, trace))
+
+	return req, body, nil
 }
 
-func createClientTrace(timing *structs.TimingInfo) *httptrace.ClientTrace {
+func createClientTrace(timing *structs.TimingInfo, tunnel bool) *httptrace.ClientTrace {
 	return &httptrace.ClientTrace{
 		DNSStart: func(_ httptrace.DNSStartInfo) {
 			timing.DNSStart = time.Now()
 		},
-		DNSDone: func(_ httptrace.DNSDoneInfo) {
-			timing.DNSDone = time.Now()
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err == nil {
+				timing.DNSDone = time.Now()
+			}
 		},
 		ConnectStart: func(_, _ string) {
 			timing.ConnectStart = time.Now()
 		},
-		ConnectDone: func(_, _ string, _ error) {
-			timing.ConnectDone = time.Now()
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				timing.ConnectDone = time.Now()
+				if tunnel {
+					timing.ProxyConnectStart = timing.ConnectDone
+				}
+			}
 		},
 		TLSHandshakeStart: func() {
 			timing.TLSStart = time.Now()
 		},
-		TLSHandshakeDone: func(_ tls.ConnectionState, _ error) {
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err != nil {
+				timing.TLS = unverifiedState(err)
+				return
+			}
+			timing.TLS = &state
 			timing.TLSDone = time.Now()
 		},
+		WroteRequest: func(_ httptrace.WroteRequestInfo) {
+			timing.RequestDone = time.Now()
+		},
 		GotFirstResponseByte: func() {
 			timing.ResponseStart = time.Now()
 		},
@@ -129,31 +425,48 @@ func createClientTrace(timing *structs.TimingInfo) *httptrace.ClientTrace {
 }
 
 func addAuthentication(req *http.Request, bearer, basic string) string {
-	if bearer != "" {
-		authHeader := BearerPrefix + bearer
+	if authHeader := authorizationHeader(bearer, basic); authHeader != "" {
 		req.Header.Set("Authorization", authHeader)
 		return authHeader
 	}
 
+	return req.Header.Get("Authorization")
+}
+
+// authorizationHeader builds the Authorization value for --bearer or --basic.
+func authorizationHeader(bearer, basic string) string {
+	if bearer != "" {
+		return BearerPrefix + bearer
+	}
+
 	if basic != "" {
-		encoded := base64.StdEncoding.EncodeToString([]byte(basic))
-		authHeader := BasicPrefix + encoded
-		req.Header.Set("Authorization", authHeader)
-		return authHeader
+		return BasicPrefix + base64.StdEncoding.EncodeToString([]byte(basic))
 	}
 
 	return ""
 }
 
-func setContentType(req *http.Request, contentType, data string) {
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+func setContentType(req *http.Request, opts structs.RequestOptions, body *requestBody) {
+	if body != nil && body.forceContentType {
+		req.Header.Set("Content-Type", body.contentType)
 		return
 	}
 
-	if data != "" {
-		req.Header.Set("Content-Type", DefaultContentType)
+	if opts.ContentType != "" {
+		req.Header.Set("Content-Type", opts.ContentType)
+		return
 	}
+
+	if body == nil || req.Header.Get("Content-Type") != "" {
+		return
+	}
+
+	if body.contentType != "" {
+		req.Header.Set("Content-Type", body.contentType)
+		return
+	}
+
+	req.Header.Set("Content-Type", DefaultContentType)
 }
 
 func readResponseBody(resp *http.Response) ([]byte, error) {
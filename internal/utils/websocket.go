@@ -0,0 +1,476 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JoaoPedr0Maciel/charm/internal/structs"
+	"github.com/JoaoPedr0Maciel/charm/internal/ui"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	DefaultWebSocketWait = 2 * time.Second
+	controlWriteTimeout  = 5 * time.Second
+	closeReplyTimeout    = 2 * time.Second
+)
+
+// ParseWebSocketScript reads the messages of a --script file, one per line.
+func ParseWebSocketScript(path string) ([]structs.WebSocketMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open script: %w", err)
+	}
+	defer file.Close()
+
+	var messages []structs.WebSocketMessage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		message, ok, err := ParseWebSocketMessage(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if ok {
+			messages = append(messages, message)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return messages, nil
+}
+
+// ParseWebSocketMessage parses one script or interactive line. Lines are sent
+// as text frames, except blank lines and # comments (skipped) and directives:
+//
+//	@binary <hex>         binary frame
+//	@ping [payload]       ping frame
+//	@sleep <duration>     pause before the next message
+//	@close [code] [text]  close the connection
+//
+// A leading backslash escapes a line that starts with @ or #.
+func ParseWebSocketMessage(line string) (structs.WebSocketMessage, bool, error) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		return structs.WebSocketMessage{}, false, nil
+	case strings.HasPrefix(line, `\`):
+		return structs.WebSocketMessage{Type: structs.FrameText, Data: []byte(line[1:])}, true, nil
+	case !strings.HasPrefix(trimmed, "@"):
+		return structs.WebSocketMessage{Type: structs.FrameText, Data: []byte(line)}, true, nil
+	}
+
+	directive, arg, _ := strings.Cut(trimmed[1:], " ")
+	arg = strings.TrimSpace(arg)
+
+	switch directive {
+	case "binary":
+		data, err := hex.DecodeString(strings.Join(strings.Fields(arg), ""))
+		if err != nil {
+			return structs.WebSocketMessage{}, false, fmt.Errorf("invalid @binary hex %q: %w", arg, err)
+		}
+		return structs.WebSocketMessage{Type: structs.FrameBinary, Data: data}, true, nil
+	case "ping":
+		return structs.WebSocketMessage{Type: structs.FramePing, Data: []byte(arg)}, true, nil
+	case "sleep":
+		delay, err := time.ParseDuration(arg)
+		if err != nil || delay < 0 {
+			return structs.WebSocketMessage{}, false, fmt.Errorf("invalid @sleep duration %q", arg)
+		}
+		return structs.WebSocketMessage{Type: structs.MessageSleep, Delay: delay}, true, nil
+	case "close":
+		message := structs.WebSocketMessage{Type: structs.FrameClose, CloseCode: websocket.CloseNormalClosure}
+		if arg == "" {
+			return message, true, nil
+		}
+		rawCode, reason, _ := strings.Cut(arg, " ")
+		code, err := strconv.Atoi(rawCode)
+		if err != nil || code < 1000 || code > 4999 {
+			return structs.WebSocketMessage{}, false, fmt.Errorf("invalid @close code %q: must be between 1000 and 4999", rawCode)
+		}
+		message.CloseCode, message.Data = code, []byte(strings.TrimSpace(reason))
+		return message, true, nil
+	}
+
+	return structs.WebSocketMessage{}, false, fmt.Errorf("unknown directive @%s (use @binary, @ping, @sleep or @close)", directive)
+}
+
+// webSocketURL accepts ws(s):// URLs as well as http(s):// ones, which are
+// switched to the matching WebSocket scheme.
+func webSocketURL(rawURL string) (*url.URL, error) {
+	parsedURL, err := validateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsedURL.Scheme {
+	case "http":
+		parsedURL.Scheme = "ws"
+	case "https":
+		parsedURL.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q (use ws://, wss://, http:// or https://)", parsedURL.Scheme)
+	}
+
+	return parsedURL, nil
+}
+
+// WebSocket opens a WebSocket connection with the same auth, headers, proxy
+// and TLS settings as HTTP requests, runs the scripted or interactive session
+// and renders every frame as it is sent or received.
+func WebSocket(opts structs.RequestOptions, wsOpts structs.WebSocketOptions) error {
+	parsedURL, err := webSocketURL(resolveURL(opts.BaseURL, opts.URL))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	mergeQuery(parsedURL, opts.Query)
+	opts.URL = parsedURL.String()
+
+	tlsConfig, err := newTLSConfig(opts.TLS)
+	if err != nil {
+		return fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	header := http.Header{}
+	for name, values := range opts.Headers {
+		header[name] = values
+	}
+	if authHeader := authorizationHeader(opts.Bearer, opts.Basic); authHeader != "" {
+		header.Set("Authorization", authHeader)
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxyFor(opts, req.URL)
+		},
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: opts.ConnectTimeout + opts.Timeout,
+		Subprotocols:     wsOpts.Subprotocols,
+	}
+
+	ctx, cancel := newSignalContext()
+	defer cancel()
+
+	sessionCtx, cancelSession := newAttemptContext(ctx, opts)
+	defer cancelSession()
+
+	timing := &structs.TimingInfo{RequestStart: time.Now()}
+	traceCtx := httptrace.WithClientTrace(sessionCtx, createClientTrace(timing, false))
+
+	conn, resp, err := dialer.DialContext(traceCtx, opts.URL, header)
+	handshakeTime := time.Since(timing.RequestStart)
+	timing.ResponseDone = time.Now()
+
+	display := structs.NewDisplay(http.MethodGet, opts.URL).
+		WithAuth(opts.Bearer, opts.Basic, header.Get("Authorization")).
+		WithTiming(handshakeTime, timing).
+		WithTLSInfo(opts.TLSInfo).
+		WithOutput(opts.Output)
+
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			// The server answered with plain HTTP (e.g. 401 or 404): show it
+			// like any other response.
+			body, _ := io.ReadAll(resp.Body)
+			display.WithHTTP(resp.Request, resp, body)
+			if err := ui.Display(*display); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			return &TransportError{Err: fmt.Errorf("websocket handshake failed: %s", resp.Status)}
+		}
+		return &TransportError{Err: displayFailure(display, newFailure(sessionCtx, err, timing, opts))}
+	}
+	defer conn.Close()
+
+	display.WithHTTP(resp.Request, resp, nil)
+	ui.DisplayWebSocketOpen(*display, conn.Subprotocol(), wsOpts.Interactive)
+
+	session := &webSocketSession{
+		conn:   conn,
+		output: opts.Output,
+		start:  time.Now(),
+		pings:  map[string]time.Time{},
+	}
+	readErr := session.run(sessionCtx, wsOpts)
+
+	summary := session.summary
+	summary.Duration = time.Since(session.start)
+	if summary.ClosedBy == "" {
+		summary.CloseCode = websocket.CloseAbnormalClosure
+		if readErr != nil {
+			summary.CloseReason = readErr.Error()
+		}
+	}
+	ui.DisplayWebSocketClose(summary, opts.Output)
+
+	if summary.ClosedBy == "" {
+		return &TransportError{Err: fmt.Errorf("websocket connection closed abnormally: %w", readErr)}
+	}
+	return nil
+}
+
+type webSocketSession struct {
+	conn   *websocket.Conn
+	output string
+	start  time.Time
+
+	// mu serializes the display and the summary, which are updated from the
+	// read loop, the writer and the control frame handlers.
+	mu        sync.Mutex
+	summary   structs.WebSocketSummary
+	pings     map[string]time.Time
+	closeSent bool
+}
+
+// run sends the messages and listens until the connection closes. The
+// session ends with a close handshake once every message was sent and
+// --wait elapsed, on @close, on Ctrl+C or when --max-time is reached.
+func (s *webSocketSession) run(ctx context.Context, wsOpts structs.WebSocketOptions) error {
+	s.conn.SetPingHandler(s.handlePing)
+	s.conn.SetPongHandler(s.handlePong)
+	s.conn.SetCloseHandler(s.handleClose)
+
+	readDone := make(chan error, 1)
+	go func() { readDone <- s.readLoop() }()
+
+	sent := make(chan struct{})
+	go func() {
+		s.send(ctx, wsOpts)
+		close(sent)
+	}()
+
+	var pings <-chan time.Time
+	if wsOpts.PingInterval > 0 {
+		ticker := time.NewTicker(wsOpts.PingInterval)
+		defer ticker.Stop()
+		pings = ticker.C
+	}
+
+	var listen <-chan time.Time
+	for {
+		select {
+		case err := <-readDone:
+			return err
+		case <-sent:
+			sent = nil
+			if wsOpts.Wait > 0 {
+				listen = time.After(wsOpts.Wait)
+			}
+		case <-listen:
+			s.close(websocket.CloseNormalClosure, "")
+			return s.awaitClose(readDone)
+		case <-ctx.Done():
+			s.close(websocket.CloseNormalClosure, "")
+			return s.awaitClose(readDone)
+		case <-pings:
+			s.ping(nil)
+		}
+	}
+}
+
+// awaitClose gives the server a moment to answer our close frame.
+func (s *webSocketSession) awaitClose(readDone <-chan error) error {
+	select {
+	case err := <-readDone:
+		return err
+	case <-time.After(closeReplyTimeout):
+		s.conn.Close()
+		return <-readDone
+	}
+}
+
+func (s *webSocketSession) readLoop() error {
+	for {
+		messageType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			// A dropped connection also surfaces as a CloseError, with the
+			// reserved 1006 code that never travels in a real close frame.
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Code != websocket.CloseAbnormalClosure {
+				return nil
+			}
+			return err
+		}
+
+		frameType := structs.FrameText
+		if messageType == websocket.BinaryMessage {
+			frameType = structs.FrameBinary
+		}
+
+		s.record(structs.WebSocketFrame{Type: frameType, Data: data})
+	}
+}
+
+// send writes the scripted messages, then the lines typed or piped on stdin
+// when the session is interactive.
+func (s *webSocketSession) send(ctx context.Context, wsOpts structs.WebSocketOptions) {
+	for _, message := range wsOpts.Messages {
+		if !s.sendMessage(ctx, message) {
+			return
+		}
+	}
+
+	if !wsOpts.Interactive {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		message, ok, err := ParseWebSocketMessage(scanner.Text())
+		if err != nil {
+			ui.DisplayWebSocketError(err)
+			continue
+		}
+		if ok && !s.sendMessage(ctx, message) {
+			return
+		}
+	}
+}
+
+// sendMessage reports whether the session can go on with the next message.
+func (s *webSocketSession) sendMessage(ctx context.Context, message structs.WebSocketMessage) bool {
+	switch message.Type {
+	case structs.MessageSleep:
+		return sleepContext(ctx, message.Delay)
+	case structs.FramePing:
+		return s.ping(message.Data)
+	case structs.FrameClose:
+		s.close(message.CloseCode, string(message.Data))
+		return false
+	}
+
+	messageType := websocket.TextMessage
+	if message.Type == structs.FrameBinary {
+		messageType = websocket.BinaryMessage
+	}
+
+	// Frames are recorded before writing so a fast reply is never shown
+	// ahead of the frame it answers.
+	s.record(structs.WebSocketFrame{Sent: true, Type: message.Type, Data: message.Data})
+	return s.conn.WriteMessage(messageType, message.Data) == nil
+}
+
+// ping sends a ping frame; without a payload, a unique one is generated so
+// the matching pong can be timed.
+func (s *webSocketSession) ping(payload []byte) bool {
+	if len(payload) == 0 {
+		payload = []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	}
+
+	s.mu.Lock()
+	s.pings[string(payload)] = time.Now()
+	s.mu.Unlock()
+
+	s.record(structs.WebSocketFrame{Sent: true, Type: structs.FramePing, Data: payload})
+	return s.conn.WriteControl(websocket.PingMessage, payload, time.Now().Add(controlWriteTimeout)) == nil
+}
+
+func (s *webSocketSession) close(code int, reason string) {
+	s.mu.Lock()
+	if s.closeSent {
+		s.mu.Unlock()
+		return
+	}
+	s.closeSent = true
+	s.mu.Unlock()
+
+	s.record(structs.WebSocketFrame{Sent: true, Type: structs.FrameClose, CloseCode: code, Data: []byte(reason)})
+
+	message := websocket.FormatCloseMessage(code, reason)
+	s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(controlWriteTimeout))
+}
+
+func (s *webSocketSession) handlePing(data string) error {
+	s.record(structs.WebSocketFrame{Type: structs.FramePing, Data: []byte(data)})
+
+	err := s.conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(controlWriteTimeout))
+	if errors.Is(err, websocket.ErrCloseSent) {
+		return nil
+	}
+	return err
+}
+
+func (s *webSocketSession) handlePong(data string) error {
+	frame := structs.WebSocketFrame{Type: structs.FramePong, Data: []byte(data)}
+
+	s.mu.Lock()
+	if sentAt, ok := s.pings[data]; ok {
+		frame.RTT = time.Since(sentAt)
+		delete(s.pings, data)
+	}
+	s.mu.Unlock()
+
+	s.record(frame)
+	return nil
+}
+
+// handleClose records the close frame and, when the server started the
+// close handshake, echoes its code back as the protocol requires.
+func (s *webSocketSession) handleClose(code int, text string) error {
+	s.record(structs.WebSocketFrame{Type: structs.FrameClose, CloseCode: code, Data: []byte(text)})
+
+	s.mu.Lock()
+	s.summary.CloseCode, s.summary.CloseReason = code, text
+	serverInitiated := !s.closeSent
+	if serverInitiated {
+		s.summary.ClosedBy = "server"
+		s.closeSent = true
+	} else {
+		s.summary.ClosedBy = "client"
+	}
+	s.mu.Unlock()
+
+	if serverInitiated {
+		if code == websocket.CloseNoStatusReceived {
+			code = websocket.CloseNormalClosure
+		}
+		message := websocket.FormatCloseMessage(code, "")
+		s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(controlWriteTimeout))
+	}
+	return nil
+}
+
+func (s *webSocketSession) record(frame structs.WebSocketFrame) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frame.Elapsed = time.Since(s.start)
+
+	switch {
+	case frame.Type == structs.FramePing && frame.Sent:
+		s.summary.Pings++
+	case frame.Type == structs.FramePong:
+		if frame.RTT > 0 {
+			s.summary.Pongs++
+			s.summary.TotalRTT += frame.RTT
+		}
+	case frame.Type == structs.FrameText || frame.Type == structs.FrameBinary:
+		if frame.Sent {
+			s.summary.Sent++
+			s.summary.BytesSent += int64(len(frame.Data))
+		} else {
+			s.summary.Received++
+			s.summary.BytesReceived += int64(len(frame.Data))
+		}
+	}
+
+	ui.DisplayWebSocketFrame(frame, s.output)
+}
@@ -0,0 +1,226 @@
+package vars
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// Variables resolves {{name}} placeholders. Names missing from the map fall
+// back to OS environment variables; names starting with $ are generators.
+type Variables map[string]string
+
+type UndefinedError struct {
+	Names []string
+}
+
+func (e *UndefinedError) Error() string {
+	placeholders := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		placeholders[i] = "{{" + name + "}}"
+	}
+	return fmt.Sprintf("undefined variables: %s (define them with --var, --env-file or environment variables)", strings.Join(placeholders, ", "))
+}
+
+// Merge copies the given sets into v; later sets override earlier ones.
+func (v Variables) Merge(sets ...map[string]string) Variables {
+	for _, set := range sets {
+		for name, value := range set {
+			v[name] = value
+		}
+	}
+	return v
+}
+
+func (v Variables) Expand(text string) (string, error) {
+	return v.expand(text, func(value string) string { return value })
+}
+
+// ExpandJSON is Expand for JSON documents: values are escaped as the
+// contents of a JSON string, so a quote or backslash cannot break out of the
+// string the placeholder sits in. Numbers and booleans are left unchanged.
+func (v Variables) ExpandJSON(text string) (string, error) {
+	return v.expand(text, escapeJSON)
+}
+
+func (v Variables) expand(text string, escape func(string) string) (string, error) {
+	var undefined []string
+	var genErr error
+
+	result := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		expression := placeholderPattern.FindStringSubmatch(match)[1]
+
+		if strings.HasPrefix(expression, "$") {
+			value, err := generate(expression)
+			if err != nil && genErr == nil {
+				genErr = err
+			}
+			return escape(value)
+		}
+
+		if value, ok := v.Lookup(expression); ok {
+			return escape(value)
+		}
+
+		if !slices.Contains(undefined, expression) {
+			undefined = append(undefined, expression)
+		}
+		return match
+	})
+
+	if genErr != nil {
+		return "", genErr
+	}
+	if len(undefined) > 0 {
+		return "", &UndefinedError{Names: undefined}
+	}
+	return result, nil
+}
+
+func escapeJSON(value string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	quoted := strings.TrimSuffix(buf.String(), "\n")
+	return quoted[1 : len(quoted)-1]
+}
+
+func (v Variables) Lookup(name string) (string, bool) {
+	if value, ok := v[name]; ok {
+		return value, true
+	}
+	return os.LookupEnv(name)
+}
+
+// ParseAssignments parses repeatable --var "key=value" flags.
+func ParseAssignments(values []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, value := range values {
+		name, varValue, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q: expected format 'key=value'", value)
+		}
+		result[name] = varValue
+	}
+	return result, nil
+}
+
+// LoadEnvFile reads a dotenv file: KEY=VALUE lines, optional "export ",
+// # comments and single or double quoted values.
+func LoadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	defer file.Close()
+
+	result := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+
+		result[name] = unquote(strings.TrimSpace(value))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	return result, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == '"' && last == '"' {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+		}
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+
+	if comment := strings.Index(value, " #"); comment != -1 {
+		return strings.TrimSpace(value[:comment])
+	}
+	return value
+}
+
+// generate evaluates built-ins such as {{$uuid}}, {{$timestamp}} and {{$randomInt 1 100}}.
+func generate(expression string) (string, error) {
+	fields := strings.Fields(expression)
+
+	switch fields[0] {
+	case "$uuid", "$guid":
+		return newUUID()
+	case "$timestamp":
+		return strconv.FormatInt(time.Now().Unix(), 10), nil
+	case "$isoTimestamp":
+		return time.Now().UTC().Format(time.RFC3339), nil
+	case "$randomInt":
+		return randomInt(fields[1:])
+	}
+
+	return "", fmt.Errorf("unknown generator {{%s}}: available are $uuid, $timestamp, $isoTimestamp and $randomInt [min max]", expression)
+}
+
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func randomInt(args []string) (string, error) {
+	minValue, maxValue := int64(0), int64(1000)
+
+	if len(args) != 0 && len(args) != 2 {
+		return "", fmt.Errorf("{{$randomInt}} expects no arguments or a min and max, e.g. {{$randomInt 1 100}}")
+	}
+
+	if len(args) == 2 {
+		var err error
+		if minValue, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			return "", fmt.Errorf("invalid {{$randomInt}} min %q", args[0])
+		}
+		if maxValue, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return "", fmt.Errorf("invalid {{$randomInt}} max %q", args[1])
+		}
+		if maxValue <= minValue {
+			return "", fmt.Errorf("{{$randomInt}} max must be greater than min")
+		}
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(maxValue-minValue))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(minValue+n.Int64(), 10), nil
+}